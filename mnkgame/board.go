@@ -3,10 +3,10 @@ package mnkgame
 import (
 	"bytes"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
-	"sync"
 	"unicode/utf8"
 )
 
@@ -14,7 +14,7 @@ import (
 // The board has a variety of optionally set attributes to make parts
 // of the game clearer such as custom labels.
 //
-// TODO(rsned): Separate rendering of board from its state management.
+// Rendering of the board is handled separately by a Renderer.
 type Board struct {
 	// the dimension of the board.
 	rows int
@@ -24,6 +24,10 @@ type Board struct {
 	// This value is capped at the shortest dimension of the board.
 	targetSize int
 
+	// exactTarget is set when only runs of exactly targetSize win. A longer
+	// run, an overline, does not count. (e.g. Standard Gomoku)
+	exactTarget bool
+
 	// cells is the actual board layout rows x cols in size.
 	cells [][]Marker
 
@@ -50,6 +54,15 @@ type Board struct {
 	rowLabelMap map[string]int
 	colLabelMap map[string]int
 
+	// index is the position of the board in its game, 0 for the main board
+	// and i for the i'th sub-board, so changes can be traced back to it.
+	index int
+
+	// When journal is set, every change made to the cells through set is
+	// added to it so that it can be undone. All the boards in a game share
+	// one journal while a move is applied.
+	journal *[]cellChange
+
 	// winTests is the set of all N-in-a-row that fit within the current boards
 	// dimensions. It is precomputed once at start time so the per-move checking
 	// can just iterate over it.
@@ -84,6 +97,55 @@ func newBoard(rows, cols, targetSize int) *Board {
 	return b
 }
 
+// Clone returns a deep copy of the board. The copy may be played on or have
+// its labels changed without affecting b.
+//
+// The winning coordinate sets are computed once from the board dimensions
+// and never modified, so they are shared between the copies.
+func (b *Board) Clone() *Board {
+	c := &Board{
+		rows:         b.rows,
+		cols:         b.cols,
+		targetSize:   b.targetSize,
+		exactTarget:  b.exactTarget,
+		cells:        make([][]Marker, len(b.cells)),
+		hasLabels:    b.hasLabels,
+		rowLabels:    slices.Clone(b.rowLabels),
+		colLabels:    slices.Clone(b.colLabels),
+		rowLabelSize: b.rowLabelSize,
+		rowLabelMap:  maps.Clone(b.rowLabelMap),
+		colLabelMap:  maps.Clone(b.colLabelMap),
+		index:        b.index,
+		winTests:     b.winTests,
+	}
+	for i, row := range b.cells {
+		c.cells[i] = slices.Clone(row)
+	}
+	return c
+}
+
+// cellChange is a record of one cell on a board changing value.
+type cellChange struct {
+	// board is the index of the board in its game that changed.
+	board int
+	coord Coord
+	from  Marker
+	to    Marker
+}
+
+// set changes the marker in the given cell, recording the change if needed.
+func (b *Board) set(c Coord, m Marker) {
+	if b.journal != nil {
+		*b.journal = append(*b.journal, cellChange{
+			board: b.index,
+			coord: c,
+			from:  b.cells[c.Row][c.Col],
+			to:    m,
+		})
+	}
+	b.cells[c.Row][c.Col] = m
+}
+
 // SetLabels sets the given set of labels for the rows and columns in the
 // board and updates the corresponding state elements of the board.
 func (b *Board) SetLabels(rowLabels, colLabels []string) {
@@ -118,13 +180,15 @@ func (b *Board) SetLabels(rowLabels, colLabels []string) {
 	for i, v := range b.colLabels {
 		b.colLabelMap[v] = i
 	}
-
 }
 
 // decodeMove applies the reverse transformation of the strings generated in
 // OpenPositions to map back to a board coordinate.
 func (b *Board) decodeMove(move string) (Coord, bool) {
 	if b.hasLabels {
+		if len(move) < b.rowLabelSize {
+			return Coord{}, false
+		}
 		row := move[0:b.rowLabelSize]
 		col := move[b.rowLabelSize:]
 		var c Coord
@@ -161,9 +225,53 @@ func (b *Board) decodeMove(move string) (Coord, bool) {
 	return coord, true
 }
 
-// ApplyMove applies the given move for the given player to the board.
-// If there are errors preventing the move, they are returned.
-func (b *Board) ApplyMove(player *Player, move string) error {
+// decodeColumn maps a column only move, either a column label or a 1-based
+// column number, back to the board column.
+func (b *Board) decodeColumn(move string) (int, bool) {
+	if b.hasLabels {
+		col, ok := b.colLabelMap[move]
+		return col, ok
+	}
+
+	if i, err := strconv.Atoi(move); (err == nil) && (i > 0 && i <= b.cols) {
+		return i - 1, true
+	}
+	return 0, false
+}
+
+// dropRow returns the lowest empty row in the given column, or false if the
+// column is already full.
+func (b *Board) dropRow(col int) (int, bool) {
+	for row := b.rows - 1; row >= 0; row-- {
+		if b.cells[row][col] == MarkerEmpty {
+			return row, true
+		}
+	}
+	return 0, false
+}
+
+// OpenColumns returns the set of columns that still have at least one empty
+// cell. If there are notation labels, the column labels are returned,
+// otherwise the 1-based column numbers are.
+func (b *Board) OpenColumns() []string {
+	var open []string
+	for j := 0; j < b.cols; j++ {
+		if b.cells[0][j] != MarkerEmpty {
+			continue
+		}
+		if b.hasLabels {
+			open = append(open, b.colLabels[j])
+		} else {
+			open = append(open, strconv.Itoa(j+1))
+		}
+	}
+	return open
+}
+
+// ApplyMove puts the marker in the cell named by the move. The marker is the
+// one the player moving uses in their game, see MNKGame.Marker. If there are
+// errors preventing the move, they are returned.
+func (b *Board) ApplyMove(marker Marker, move string) error {
 	m, ok := b.decodeMove(move)
 	if !ok {
 		return fmt.Errorf("Unable to decipher the requested move: %q", move)
@@ -173,7 +281,7 @@ func (b *Board) ApplyMove(player *Player, move string) error {
 		return fmt.Errorf("Move not available")
 	}
 
-	b.cells[m.Row][m.Col] = player.marker
+	b.set(m, marker)
 	return nil
 }
 
@@ -199,406 +307,155 @@ func (b *Board) OpenPositions() []string {
 	return open
 }
 
-// BoardOptions packages up the various settings used when rendering the game board.
-type BoardOptions struct {
-	HasOuterBorder bool // Should there be a line around the labels outside the main board.
-	HasInnerBorder bool // Should there be a line around the main board area.
-	HasInnerGrid   bool // Should we render the lines separating each row and column.
-	HasLabels      bool // Do we have labels to show.
-	LabelWidth     int  // Width of longest label to be displayed.
-	MarkerWidth    int  // Width of the widest player marker symbol.
-	Padding        int  // Amount of whitespace on either side of labels and markers.
-}
-
-// Various board border and separator tokens.
-// See https://en.wikipedia.org/wiki/Box_Drawing for more symbols.
-const (
-	cornerTopLeft          = "┌"
-	cornerTopLeftThick     = "┏"
-	cornerTopRight         = "┐"
-	cornerTopRightThick    = "┓"
-	cornerBottomLeft       = "└"
-	cornerBottomLeftThick  = "┗"
-	cornerBottomRight      = "┘"
-	cornerBottomRightThick = "┛"
-	lineHorizontal         = "─"
-	lineHorizontalThick    = "━"
-	lineVertical           = "│"
-	lineVerticalThick      = "┃"
-	cross                  = "┼"
-	crossThick             = "╋"
-	teeLeft                = "├"
-	teeLeftThick           = "┣"
-	teeRight               = "┤"
-	teeRightThick          = "┫"
-	teeUp                  = "┴"
-	teeUpThick             = "┻"
-	teeDown                = "┬"
-	teeDownThick           = "┳"
-
-	// whiteSpace is a block of text we can substring from.
-	whiteSpace = "                                            "
-)
-
-var (
-	cellBorder = []rune(strings.Repeat(lineHorizontal, 20))
-)
-
-var (
-	boardCache = map[string]string{}
-)
-
 // String returns a fixed width layout text version of the current boards state.
 //
 // TODO(rsned): Consider renaming this method and leaving String() as a simpler
 // state dump of the instance.
 func (b *Board) String() string {
-
-	return b.renderBoard(nil)
+	var buf bytes.Buffer
+	defaultTextRenderer.Render(&buf, b)
+	return buf.String()
 }
 
-// generateStaticElements computes the dimensions of the board and renders
-// the parts of the board that don't change every iteration for the rendering
-// throughout the remainder of the run.
-func (b *Board) generateStaticElements(bo *BoardOptions) {
-	// Figure out the overall width of the output starting with the number of
-	// columns plus padding on either side.
-	boardWidth := b.cols * (bo.MarkerWidth + 2*bo.Padding)
-	if bo.HasOuterBorder {
-		boardWidth += utf8.RuneCountInString(cornerTopLeftThick) +
-			utf8.RuneCountInString(cornerTopRightThick)
-	}
-	if bo.HasInnerBorder {
-		boardWidth += utf8.RuneCountInString(cornerTopLeft) +
-			utf8.RuneCountInString(cornerTopRight)
-	}
-	if bo.HasInnerGrid {
-		boardWidth += (b.cols - 1) * utf8.RuneCountInString(lineVertical)
-	}
-	if bo.HasLabels {
-		// Add spacing to left and right sides of the board.
-		boardWidth += 2 * (bo.LabelWidth + 2*bo.Padding)
-	}
-
-	// -----------------------------------------------
-	// Top and bottom outer border
-	// -----------------------------------------------
-
-	tob := cornerTopLeftThick +
-		strings.Repeat(lineHorizontalThick, boardWidth-2) +
-		cornerTopRightThick +
-		"\n"
-	boardCache["topOuterBorder"] = tob
-
-	bob := cornerBottomLeftThick +
-		strings.Repeat(lineHorizontalThick, boardWidth-2) +
-		cornerBottomRightThick +
-		"\n"
-	boardCache["botOuterBorder"] = bob
-
-	// -----------------------------------------------
-	// Top and bottom labels
-	// -----------------------------------------------
-
-	var rowBuf bytes.Buffer
-	// If has outer border
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
-	}
-	// Row label padding
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
-	}
-	// Inner border padding
-	if bo.HasInnerBorder {
-		rowBuf.WriteString(" ")
-	}
-	for i, col := range b.colLabels {
-		rowBuf.WriteString(fmt.Sprintf("%s%s%s", whiteSpace[0:bo.Padding],
-			col, whiteSpace[0:bo.Padding]))
-		if bo.HasInnerGrid && i != b.cols-1 {
-			rowBuf.WriteString(" ")
-		}
-	}
-	// Inner border padding
-	if bo.HasInnerBorder {
-		rowBuf.WriteString(" ")
-	}
-	// Row label padding
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
-	}
-	// If has outer border
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
-	}
-	rowBuf.WriteString("\n")
-	boardCache["colLabels"] = rowBuf.String()
-
-	// -----------------------------------------------
-	// Top and bottom inner borders
-	// -----------------------------------------------
-
-	rowBuf.Reset()
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
-	}
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
-	}
-
-	// If has inner border
-	if bo.HasInnerBorder {
-		rowBuf.WriteString(cornerTopLeft)
-	}
-
-	for i := range b.cols {
-		if bo.HasInnerBorder {
-			rowBuf.WriteString(string(cellBorder[0:(bo.MarkerWidth + 2*bo.Padding)]))
-		} else {
-			rowBuf.WriteString(whiteSpace[0 : bo.MarkerWidth+2*bo.Padding])
-		}
-		if i != b.cols-1 {
-			if bo.HasInnerBorder {
-				if bo.HasInnerGrid {
-					rowBuf.WriteString(teeDown)
-				} else {
-					rowBuf.WriteString(lineHorizontal)
-				}
-			}
-		}
-	}
-
-	if bo.HasInnerBorder {
-		rowBuf.WriteString(cornerTopRight)
-	}
-
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
-	}
-
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
-	}
-
-	rowBuf.WriteString("\n")
+// RenderWithOptions returns a text version of the current boards state laid
+// out using the given options.
+func (b *Board) RenderWithOptions(opts BoardOptions) string {
+	var buf bytes.Buffer
+	defaultTextRenderer.renderWithOptions(&buf, b, opts, nil)
+	return buf.String()
+}
 
-	boardCache["topInnerBorder"] = rowBuf.String()
+// Rows returns the number of rows on the board.
+func (b *Board) Rows() int {
+	return b.rows
+}
 
-	// Bottom inner border
-	rowBuf.Reset()
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
-	}
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
-	}
+// Cols returns the number of columns on the board.
+func (b *Board) Cols() int {
+	return b.cols
+}
 
-	// If has inner border
-	if bo.HasInnerBorder {
-		rowBuf.WriteString(cornerBottomLeft)
-	}
+// Cell returns the marker in the given cell.
+func (b *Board) Cell(row, col int) Marker {
+	return b.cells[row][col]
+}
 
-	for i := range b.cols {
-		if bo.HasInnerBorder {
-			rowBuf.WriteString(string(cellBorder[0:(bo.MarkerWidth + 2*bo.Padding)]))
-		} else {
-			rowBuf.WriteString(whiteSpace[0 : bo.MarkerWidth+2*bo.Padding])
-		}
-		if i != b.cols-1 {
-			if bo.HasInnerBorder {
-				if bo.HasInnerGrid {
-					rowBuf.WriteString(teeUp)
-				} else {
-					rowBuf.WriteString(lineHorizontal)
-				}
-			} else {
-				rowBuf.WriteString(" ")
-			}
-		}
-	}
+// HasLabels reports if the board has notation labels set.
+func (b *Board) HasLabels() bool {
+	return b.hasLabels
+}
 
-	if bo.HasInnerBorder {
-		rowBuf.WriteString(cornerBottomRight)
-	}
+// RowLabels returns a copy of the notation labels for the rows.
+func (b *Board) RowLabels() []string {
+	return slices.Clone(b.rowLabels)
+}
 
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
-	}
+// ColLabels returns a copy of the notation labels for the columns.
+func (b *Board) ColLabels() []string {
+	return slices.Clone(b.colLabels)
+}
 
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
+// checkOutcome tests a given set of coords for the given marker to see if
+// there is a full match.
+func (b *Board) checkOutcome(coords Coords, m Marker) bool {
+	if len(coords) != b.targetSize {
+		return false
 	}
 
-	rowBuf.WriteString("\n")
-
-	boardCache["bottomInnerBorder"] = rowBuf.String()
-
-	// -----------------------------------------------
-	// Inner grid separator lines
-	// -----------------------------------------------
-
-	// TODO(rsned): To allow for vertical padding, generate the same row without
-	// the marker values added in.
-
-	rowBuf.Reset()
-	// If has outer border
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
-	}
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
+	win := true
+	for _, c := range coords {
+		win = win && (b.cells[c.Row][c.Col] == m)
 	}
+	return win && !(b.exactTarget && b.overline(coords, m))
+}
 
-	for i := range b.cols {
-		switch i {
-		case 0: // Opening cell of the line.
-
-			if bo.HasInnerBorder {
-				if bo.HasInnerGrid {
-					rowBuf.WriteString(teeLeft)
-				} else {
-					rowBuf.WriteString(lineVertical)
-				}
-			}
-			rowBuf.WriteString(string(cellBorder[0:(bo.MarkerWidth + 2*bo.Padding)]))
-			rowBuf.WriteString(cross)
-		case b.cols - 1: // Closing cell of the line.
-
-			rowBuf.WriteString(string(cellBorder[0:(bo.MarkerWidth + 2*bo.Padding)]))
-
-			if bo.HasInnerBorder {
-				if bo.HasInnerGrid {
-					rowBuf.WriteString(teeRight)
-				} else {
-					rowBuf.WriteString(lineVertical)
-				}
-			}
-		default:
-			// Inner cells along the separator row are basically "---+"
-			if bo.HasInnerGrid {
-				rowBuf.WriteString(string(cellBorder[0:(bo.MarkerWidth + 2*bo.Padding)]))
-				rowBuf.WriteString(cross)
-			}
-		}
+// overline reports if the line of coords continues past either end with
+// more of the same marker, making it part of a longer run.
+func (b *Board) overline(coords Coords, m Marker) bool {
+	if len(coords) < 2 {
+		return false
 	}
+	first, last := coords[0], coords[len(coords)-1]
+	dRow, dCol := coords[1].Row-first.Row, coords[1].Col-first.Col
 
-	if bo.HasLabels {
-		rowBuf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
-	}
+	return b.markerAt(first.Row-dRow, first.Col-dCol) == m ||
+		b.markerAt(last.Row+dRow, last.Col+dCol) == m
+}
 
-	if bo.HasOuterBorder {
-		rowBuf.WriteString(lineVerticalThick)
+// markerAt returns the marker in the given cell, or MarkerEmpty if the cell
+// is off the board.
+func (b *Board) markerAt(row, col int) Marker {
+	if row < 0 || row >= b.rows || col < 0 || col >= b.cols {
+		return MarkerEmpty
 	}
-	rowBuf.WriteString("\n")
-	boardCache["innerSeparator"] = rowBuf.String()
-
+	return b.cells[row][col]
 }
 
-func (b *Board) renderBoard(bo *BoardOptions) string {
-	if bo == nil {
-		bo = &BoardOptions{
-			HasOuterBorder: true,
-			HasInnerBorder: true,
-			HasInnerGrid:   true,
-			HasLabels:      b.hasLabels,
-			LabelWidth:     b.rowLabelSize,
-			MarkerWidth:    1,
-			Padding:        4,
+// full reports if every cell on the board has been filled.
+func (b *Board) full() bool {
+	for _, row := range b.cells {
+		if slices.Contains(row, MarkerEmpty) {
+			return false
 		}
 	}
+	return true
+}
 
-	var once sync.Once
-	once.Do(func() { b.generateStaticElements(bo) })
-	var buf bytes.Buffer
-
-	if bo.HasOuterBorder {
-		buf.WriteString(boardCache["topOuterBorder"])
-	}
-
-	if bo.HasLabels {
-		buf.WriteString(boardCache["colLabels"])
-	}
-
-	// If has inner border
-	if bo.HasInnerBorder {
-		buf.WriteString(boardCache["topInnerBorder"])
-	}
-
-	// Main board elements
-	for i, row := range b.cells {
-		if bo.HasOuterBorder {
-			buf.WriteString(lineVerticalThick)
-		}
-		if bo.HasLabels {
-			buf.WriteString(fmt.Sprintf("%s%s%s", whiteSpace[0:bo.Padding],
-				b.rowLabels[i], whiteSpace[0:bo.Padding]))
-		}
-		if bo.HasInnerBorder {
-			buf.WriteString(lineVertical)
-		}
-
-		// For each active cell in this row of the board
-		for i, col := range row {
-			buf.WriteString(fmt.Sprintf("%s%s%s", whiteSpace[0:bo.Padding],
-				col, whiteSpace[0:bo.Padding]))
-			if i != b.cols-1 {
-				if bo.HasInnerGrid {
-					buf.WriteString(lineVertical)
-				}
+// lineScore is a rough measure of how strong the board is for the player
+// placing mine. Each potential winning line that the opponent has not
+// blocked adds the square of the number of mine already in it.
+func (b *Board) lineScore(mine, opponent Marker) int {
+	score := 0
+	for _, coords := range b.winTests {
+		n := 0
+		blocked := false
+		for _, c := range coords {
+			switch b.cells[c.Row][c.Col] {
+			case mine:
+				n++
+			case opponent:
+				blocked = true
 			}
 		}
-
-		if bo.HasInnerBorder {
-			buf.WriteString(lineVertical)
+		if !blocked {
+			score += n * n
 		}
-		if bo.HasLabels {
-			buf.WriteString(whiteSpace[0 : bo.LabelWidth+2*bo.Padding])
+	}
+	return score
+}
+
+// WinningLine returns the cells of the first winning line on the board, a
+// full line of the same marker that is not an overline when only exact runs
+// count. If no line has been completed, nil is
+// returned.
+func (b *Board) WinningLine() Coords {
+	for _, coords := range b.winTests {
+		if len(coords) != b.targetSize {
+			continue
 		}
-		if bo.HasOuterBorder {
-			buf.WriteString(lineVerticalThick)
+		m := b.cells[coords[0].Row][coords[0].Col]
+		if m == MarkerEmpty || m == MarkerBlocked {
+			continue
 		}
-		buf.WriteString("\n")
-
-		if bo.HasInnerGrid && i != b.cols-1 {
-			buf.WriteString(boardCache["innerSeparator"])
+		win := true
+		for _, c := range coords[1:] {
+			win = win && b.cells[c.Row][c.Col] == m
 		}
-		if i == b.cols-1 && bo.HasInnerBorder {
-			buf.WriteString(boardCache["bottomInnerBorder"])
+		if win && !(b.exactTarget && b.overline(coords, m)) {
+			return slices.Clone(coords)
 		}
 	}
-
-	if bo.HasLabels {
-		buf.WriteString(boardCache["colLabels"])
-	}
-
-	if bo.HasOuterBorder {
-		buf.WriteString(boardCache["botOuterBorder"])
-	}
-
-	return buf.String()
-}
-
-// checkOutcome tests a given set of coords for the given player to see if
-// there is a full match.
-func (b *Board) checkOutcome(coords Coords, p *Player) bool {
-	if len(coords) != b.targetSize {
-		return false
-	}
-
-	win := true
-	for _, c := range coords {
-		win = win && (b.cells[c.Row][c.Col] == p.marker)
-	}
-	return win
+	return nil
 }
 
-// Outcome reports the game outcome state for both players.
-func (b *Board) Outcome() (player1, player2 Outcome) {
+// Outcome reports the game outcome state for the two players in the game,
+// based on the markers m1 and m2 they play with in their game, see
+// MNKGame.Marker.
+func (b *Board) Outcome(m1, m2 Marker) (player1, player2 Outcome) {
 	var win bool
 	// Check P1
 	for _, coords := range b.winTests {
-		win = win || b.checkOutcome(coords, Player1)
+		win = win || b.checkOutcome(coords, m1)
 	}
 	if win {
 		return OutcomeWin, OutcomeLoss
@@ -607,14 +464,14 @@ func (b *Board) Outcome() (player1, player2 Outcome) {
 	// Check P2
 	win = false
 	for _, coords := range b.winTests {
-		win = win || b.checkOutcome(coords, Player2)
+		win = win || b.checkOutcome(coords, m2)
 	}
 	if win {
 		return OutcomeLoss, OutcomeWin
 	}
 
 	// Check if board is full.
-	if len(b.OpenPositions()) == 0 {
+	if b.full() {
 		return OutcomeDraw, OutcomeDraw
 	}
 
@@ -623,84 +480,74 @@ func (b *Board) Outcome() (player1, player2 Outcome) {
 
 // generateAllWinningCoordinateSets is used to figure out based on the board
 // parameters all sets of coordinates that represent winning sequences.
-//
-// TODO(rsned): Consider moving this to a standalone method instead of relying
-// on the method to get values from the Board.
 func (b *Board) generateAllWinningCoordinateSets() CoordsList {
-	potentialWins := CoordsList{}
-
-	// Start at the origin corner and walk all the cells in order, top
-	// left to botton right. For each cell attempt to generate the horizontal,
-	// vertical, and both diagonals going rightward and downward.
-	// If there are Board.targetSize cells available in the given direction,
-	// grab that many coordinates and save it. Then move on to the next cell
-	// until complete.
-	for row := 0; row < b.rows; row++ {
-		for col := 0; col < b.cols; col++ {
-			// Horizontal
-			if col <= (b.cols - b.targetSize) {
-				vals := Coords{}
-				for k := 0; k < b.targetSize; k++ {
-					vals.Add(Coord{
-						Row: row,
-						Col: col + k,
-					})
-				}
-				slices.SortFunc(vals, coordCompare)
-				potentialWins.Add(vals)
-			}
-
-			// Vertical
-			if row <= (b.rows - b.targetSize) {
-				vals := Coords{}
-				for k := 0; k < b.targetSize; k++ {
-					vals.Add(Coord{
-						Row: row + k,
-						Col: col,
-					})
-				}
-				slices.SortFunc(vals, coordCompare)
-				potentialWins.Add(vals)
-			}
+	return generateWinningLines(1, b.rows, b.cols, b.targetSize)
+}
 
-			// Diagonal TL->BR
-			// Because we increase by 1 step in both directions,
-			// The last row and col we can use is the difference between
-			// the number of rows minus the target size.  e.g. With a
-			// target of 2 and 2 rows, the only cell is row 0. Same
-			// on the col side.  If the rows is 5 and target is 2, then
-			// row 3 would be the final choice. etc. etc.
-			if row <= (b.rows-b.targetSize) && col <= (b.cols-b.targetSize) {
-				vals := Coords{}
-				for k := 0; k < b.targetSize; k++ {
-					vals.Add(Coord{
-						Row: row + k,
-						Col: col + k,
-					})
-				}
-				slices.SortFunc(vals, coordCompare)
-				potentialWins.Add(vals)
-			}
+// lineDirections are the 13 directions, as layer, row, and column steps, a
+// line can run in on a three dimensional board. Only one of each pair of
+// opposite directions is included, the one that runs forward in its first
+// step that isn't zero. The first four stay within a single layer.
+var lineDirections = [][3]int{
+	// Horizontal, vertical, and both diagonals within a layer.
+	{0, 0, 1}, {0, 1, 0}, {0, 1, 1}, {0, 1, -1},
+	// Straight down through the layers.
+	{1, 0, 0},
+	// Diagonals through the layers along a row or column.
+	{1, 0, 1}, {1, 0, -1}, {1, 1, 0}, {1, -1, 0},
+	// The four corner to corner diagonals.
+	{1, 1, 1}, {1, 1, -1}, {1, -1, 1}, {1, -1, -1},
+}
 
-			// Diagonal BL->TR
-			// This direction, the rows will grow 'upwards' aka, row will
-			// decrease while col will increase. The row just has to
-			// be at least targetSize so it won't exceed the bounds when
-			// subtracting targetSize steps. Columns just uses the same as prev.
-			if (row+1) >= b.targetSize && col <= b.cols-b.targetSize {
-				vals := Coords{}
-				for k := 0; k < b.targetSize; k++ {
-					vals.Add(Coord{
-						Row: row - k,
-						Col: col + k,
-					})
+// generateWinningLines returns every line of size cells in a row that fits on
+// a board of the given dimensions. A board with one layer only has lines in
+// the four flat directions.
+//
+// Each line is sorted, and the lines are returned in sorted order.
+func generateWinningLines(layers, rows, cols, size int) CoordsList {
+	inside := func(c Coord) bool {
+		return c.Layer >= 0 && c.Layer < layers &&
+			c.Row >= 0 && c.Row < rows &&
+			c.Col >= 0 && c.Col < cols
+	}
+
+	// A single cell runs in every direction, so only count it once.
+	directions := lineDirections
+	if size == 1 {
+		directions = directions[:1]
+	}
+
+	// Start from every cell and walk size cells in each direction. If the
+	// last cell is still on the board, the whole line is.
+	lines := CoordsList{}
+	for layer := 0; layer < layers; layer++ {
+		for row := 0; row < rows; row++ {
+			for col := 0; col < cols; col++ {
+				for _, d := range directions {
+					end := Coord{
+						Layer: layer + (size-1)*d[0],
+						Row:   row + (size-1)*d[1],
+						Col:   col + (size-1)*d[2],
+					}
+					if !inside(end) {
+						continue
+					}
+
+					line := make(Coords, size)
+					for k := range line {
+						line[k] = Coord{
+							Layer: layer + k*d[0],
+							Row:   row + k*d[1],
+							Col:   col + k*d[2],
+						}
+					}
+					slices.SortFunc(line, coordCompare)
+					lines = append(lines, line)
 				}
-				slices.SortFunc(vals, coordCompare)
-				potentialWins.Add(vals)
 			}
 		}
 	}
 
-	slices.SortFunc(potentialWins, coordSliceCompare)
-	return potentialWins
+	slices.SortFunc(lines, coordSliceCompare)
+	return lines
 }
@@ -3,6 +3,7 @@ package mnkgame
 import (
 	"fmt"
 	"slices"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -98,7 +99,7 @@ func TestBoardSetLabels(t *testing.T) {
 func TestBoardApplyMove(t *testing.T) {
 	tests := []struct {
 		board   *Board
-		player  *Player
+		marker  Marker
 		move    string
 		wantErr bool
 	}{
@@ -113,17 +114,17 @@ func TestBoardApplyMove(t *testing.T) {
 					[]Marker{MarkerEmpty, MarkerEmpty},
 				},
 			},
-			player:  Player1,
+			marker:  MarkerX,
 			move:    "A1",
 			wantErr: true,
 		},
 	}
 
 	for _, test := range tests {
-		gotErr := test.board.ApplyMove(test.player, test.move)
+		gotErr := test.board.ApplyMove(test.marker, test.move)
 		if (gotErr != nil) != test.wantErr {
-			t.Errorf("ApplyMove(player, %q) error != nil = %v, want %v",
-				test.move, gotErr != nil, test.wantErr)
+			t.Errorf("ApplyMove(%v, %q) error != nil = %v, want %v",
+				test.marker, test.move, gotErr != nil, test.wantErr)
 		}
 
 	}
@@ -725,7 +726,7 @@ func TestBoardCheckOutcome(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		if got := test.board.checkOutcome(test.coords, test.player); got != test.want {
+		if got := test.board.checkOutcome(test.coords, test.player.marker); got != test.want {
 			t.Errorf("checkOutcome(%+v, %+v) = %v, want %v",
 				test.coords, test.player, got, test.want)
 		}
@@ -801,7 +802,7 @@ func TestBoardOutcome(t *testing.T) {
 	for _, test := range tests {
 		test.board.winTests = test.board.generateAllWinningCoordinateSets()
 
-		gotp1, gotp2 := test.board.Outcome()
+		gotp1, gotp2 := test.board.Outcome(MarkerX, MarkerWhiteStone)
 		if gotp1 != test.p1Outcome {
 			t.Errorf("Outcome() = %v, %v, want player 1 %v",
 				gotp1, gotp2, test.p1Outcome)
@@ -815,3 +816,91 @@ func TestBoardOutcome(t *testing.T) {
 }
 
 // String() isn't tested since it's just a change-detector test.
+
+func TestBoardStringConcurrent(t *testing.T) {
+	// Boards of different sizes rendered at the same time must not share
+	// their cached borders.
+	ttt := TicTacToe(Player1, Player2).board
+	c4 := Connect4(Player1, Player2).board
+	wantTTT := ttt.String()
+	wantC4 := c4.String()
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 100)
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if got := ttt.String(); got != wantTTT {
+				errs <- got
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if got := c4.String(); got != wantC4 {
+				errs <- got
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for got := range errs {
+		t.Errorf("String() rendered concurrently =\n%s", got)
+	}
+}
+
+func TestBoardWinningLine(t *testing.T) {
+	tests := []struct {
+		board *Board
+		want  Coords
+	}{
+		{
+			// No winner.
+			board: &Board{
+				rows:       2,
+				cols:       2,
+				targetSize: 2,
+				cells: [][]Marker{
+					[]Marker{MarkerX, MarkerEmpty},
+					[]Marker{MarkerWhiteStone, MarkerEmpty},
+				},
+			},
+			want: nil,
+		},
+		{
+			// Diagonal win.
+			board: &Board{
+				rows:       2,
+				cols:       2,
+				targetSize: 2,
+				cells: [][]Marker{
+					[]Marker{MarkerX, MarkerWhiteStone},
+					[]Marker{MarkerEmpty, MarkerX},
+				},
+			},
+			want: Coords{{Row: 0, Col: 0}, {Row: 1, Col: 1}},
+		},
+		{
+			// Vertical win.
+			board: &Board{
+				rows:       3,
+				cols:       3,
+				targetSize: 3,
+				cells: [][]Marker{
+					[]Marker{MarkerX, MarkerEmpty, MarkerWhiteStone},
+					[]Marker{MarkerEmpty, MarkerX, MarkerWhiteStone},
+					[]Marker{MarkerX, MarkerEmpty, MarkerWhiteStone},
+				},
+			},
+			want: Coords{{Row: 0, Col: 2}, {Row: 1, Col: 2}, {Row: 2, Col: 2}},
+		},
+	}
+
+	for _, test := range tests {
+		test.board.winTests = test.board.generateAllWinningCoordinateSets()
+		if got := test.board.WinningLine(); !cmp.Equal(got, test.want, cmpopts.EquateEmpty()) {
+			t.Errorf("WinningLine() = %v, want %v", got, test.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package mnkgame
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+)
+
+// MoveChooser is the strategy a player uses to pick their next move, be it
+// a human typing at a terminal or a computer searching the board.
+type MoveChooser interface {
+	// ChooseMove returns the move the player whose turn it is in the game
+	// wishes to make. The returned move is one the game will accept, usually
+	// one of game.PotentialMoves().
+	ChooseMove(ctx context.Context, game *MNKGame) (string, error)
+}
+
+// HumanChooser prompts for and reads moves from a human player.
+type HumanChooser struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewHumanChooser returns a chooser that writes its prompts to w and reads
+// the players choice from r one line at a time.
+func NewHumanChooser(r io.Reader, w io.Writer) *HumanChooser {
+	return &HumanChooser{
+		in:  bufio.NewScanner(r),
+		out: w,
+	}
+}
+
+// ChooseMove prompts the human for a move until a valid one is entered. Any
+// notation the game accepts is valid, such as both stones of a Connect6 turn
+// in either order, not only those listed.
+//
+// Reading from the input can not be interrupted, so the context is only
+// checked between prompts.
+func (h *HumanChooser) ChooseMove(ctx context.Context, game *MNKGame) (string, error) {
+	moves := game.PotentialMoves()
+
+	// TODO(rsned): As a feature request, differentiate between bad
+	// text input (e.g., "TR" vs "2qq123uu*)AS", and position already
+	// taken (e.g. player 2 enters the postion of where player 1 just
+	// played.)
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(h.out, "Select square: %+v\n", moves)
+		if !h.in.Scan() {
+			if err := h.in.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+
+		entry := strings.TrimSpace(h.in.Text())
+		if entry == "" {
+			continue
+		}
+		if slices.Contains(moves, entry) || game.Clone().ApplyMove(game.ToMove(), entry) == nil {
+			return entry, nil
+		}
+
+		fmt.Fprintf(h.out, "Invalid entry %q, please try again.\n", entry)
+	}
+}
+
+// RandomChooser picks uniformly from the available moves.
+type RandomChooser struct {
+	rng *rand.Rand
+}
+
+// NewRandomChooser returns a chooser that draws from the given source of
+// randomness. If rng is nil, the math/rand top level functions are used.
+func NewRandomChooser(rng *rand.Rand) *RandomChooser {
+	return &RandomChooser{rng: rng}
+}
+
+// ChooseMove returns one of the available moves at random.
+func (r *RandomChooser) ChooseMove(ctx context.Context, game *MNKGame) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	moves := game.PotentialMoves()
+	if len(moves) == 0 {
+		return "", fmt.Errorf("No moves available")
+	}
+	return moves[intn(r.rng, len(moves))], nil
+}
+
+// AIChooser is a computer player that looks one move ahead. It takes a
+// winning move if there is one, blocks the opponents winning move if there
+// is one, and otherwise plays the move that builds the most open lines, or
+// that the rules score highest for games where lines are not the measure,
+// such as Qubic or Notakto.
+type AIChooser struct {
+	rng *rand.Rand
+}
+
+// NewAIChooser returns an AI chooser using rng to break ties between equally
+// good moves. If rng is nil, the math/rand top level functions are used.
+func NewAIChooser(rng *rand.Rand) *AIChooser {
+	return &AIChooser{rng: rng}
+}
+
+// ChooseMove returns the best move found for the player whose turn it is.
+func (a *AIChooser) ChooseMove(ctx context.Context, game *MNKGame) (string, error) {
+	moves := game.PotentialMoves()
+	if len(moves) == 0 {
+		return "", fmt.Errorf("No moves available")
+	}
+
+	me := game.ToMove()
+	opponent := game.opponent(me)
+
+	// Try the moves out on a copy so the callers game isn't changed.
+	g := game.Clone()
+
+	// Take a win if one is available.
+	for _, move := range moves {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if g.wins(me, move) {
+			return move, nil
+		}
+	}
+
+	// Otherwise stop the opponent from winning on their next turn.
+	for _, move := range moves {
+		if g.wins(opponent, move) {
+			return move, nil
+		}
+	}
+
+	// Lastly, pick the move that leaves the strongest position. In misère
+	// play the fewest lines are wanted, and a move that loses right away,
+	// such as completing a line, is only taken if there is no other.
+	var best []string
+	bestScore := math.MinInt
+	for _, move := range moves {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if g.ApplyMove(me, move) != nil {
+			continue
+		}
+		score := g.score(me)
+		if g.misere {
+			score = -score
+		}
+		if g.Outcome(me) == OutcomeLoss {
+			score = math.MinInt
+		}
+		g.Undo()
+		switch {
+		case best == nil || score > bestScore:
+			bestScore = score
+			best = []string{move}
+		case score == bestScore:
+			best = append(best, move)
+		}
+	}
+	if len(best) == 0 {
+		return "", fmt.Errorf("No legal moves available")
+	}
+
+	return best[intn(a.rng, len(best))], nil
+}
+
+// intn returns a random value in [0,n) from rng, or from the math/rand top
+// level source if rng is nil.
+func intn(rng *rand.Rand, n int) int {
+	if rng == nil {
+		return rand.Intn(n)
+	}
+	return rng.Intn(n)
+}
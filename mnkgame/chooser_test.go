@@ -0,0 +1,256 @@
+package mnkgame
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHumanChooser(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			// First entry is valid.
+			input: "TL\n",
+			want:  "TL",
+		},
+		{
+			// Blank lines and invalid entries are skipped.
+			input: "\nZZ\n  CC  \n",
+			want:  "CC",
+		},
+		{
+			// Input runs out before a valid entry.
+			input:   "ZZ\n",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		game := TicTacToe(Player1, Player2)
+		h := NewHumanChooser(strings.NewReader(test.input), io.Discard)
+		got, err := h.ChooseMove(context.Background(), game)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ChooseMove() with input %q error = %v, want error %v",
+				test.input, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ChooseMove() with input %q = %q, want %q", test.input, got, test.want)
+		}
+	}
+}
+
+func TestRandomChooser(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	r := NewRandomChooser(rand.New(rand.NewSource(1)))
+
+	for i := 0; i < 9; i++ {
+		moves := game.PotentialMoves()
+		move, err := r.ChooseMove(context.Background(), game)
+		if err != nil {
+			t.Fatalf("ChooseMove() error = %v", err)
+		}
+		if !slices.Contains(moves, move) {
+			t.Fatalf("ChooseMove() = %q, want one of %v", move, moves)
+		}
+		if err := game.ApplyMove(game.ToMove(), move); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", move, err)
+		}
+		if p1, _ := game.Outcomes(); p1 != OutcomeIncomplete {
+			break
+		}
+	}
+}
+
+func TestAIChooser(t *testing.T) {
+	tests := []struct {
+		moves []string
+		want  string
+	}{
+		{
+			// Player 1 to move and can win on the top row.
+			moves: []string{"TL", "CL", "TC", "CC"},
+			want:  "TR",
+		},
+		{
+			// Player 2 to move and must block the diagonal.
+			moves: []string{"TL", "TC", "CC"},
+			want:  "BR",
+		},
+		{
+			// Player 2 to move, can win or block, winning is preferred.
+			moves: []string{"TL", "CL", "TC", "CC", "BR"},
+			want:  "CR",
+		},
+	}
+
+	for _, test := range tests {
+		game := TicTacToe(Player1, Player2)
+		for _, m := range test.moves {
+			if err := game.ApplyMove(game.ToMove(), m); err != nil {
+				t.Fatalf("ApplyMove(%q) error = %v", m, err)
+			}
+		}
+
+		a := NewAIChooser(rand.New(rand.NewSource(1)))
+		got, err := a.ChooseMove(context.Background(), game)
+		if err != nil {
+			t.Errorf("ChooseMove() after %v error = %v", test.moves, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ChooseMove() after %v = %q, want %q", test.moves, got, test.want)
+		}
+	}
+}
+
+func TestAIChooserMisere(t *testing.T) {
+	// Player 1 to move, and completing the top row would lose.
+	game := TicTacToe(Player1, Player2)
+	game.SetMisere(true)
+	for _, m := range []string{"TL", "CL", "TC", "CC"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+
+	for seed := int64(0); seed < 10; seed++ {
+		a := NewAIChooser(rand.New(rand.NewSource(seed)))
+		got, err := a.ChooseMove(context.Background(), game)
+		if err != nil {
+			t.Fatalf("ChooseMove() error = %v", err)
+		}
+		if got == "TR" {
+			t.Errorf("ChooseMove() with seed %d = %q, which completes a line", seed, got)
+		}
+	}
+}
+
+func TestAIChooserScore(t *testing.T) {
+	tests := []struct {
+		name  string
+		game  func(p1, p2 *Player) *MNKGame
+		moves []string
+		want  []string
+	}{
+		{
+			// The corners and the centers of the cube are on the most lines.
+			name: "qubic",
+			game: Qubic,
+			want: []string{
+				"1a1", "1a4", "1d1", "1d4", "4a1", "4a4", "4d1", "4d4",
+				"2b2", "2b3", "2c2", "2c3", "3b2", "3b3", "3c2", "3c3",
+			},
+		},
+		{
+			// Leave the opponent the fewest moves that don't kill the board.
+			name: "notakto",
+			game: func(p1, p2 *Player) *MNKGame {
+				return Notakto(1, p1, p2)
+			},
+			moves: []string{"1TL", "1BR"},
+			want:  []string{"1TR", "1BL"},
+		},
+	}
+
+	for _, test := range tests {
+		game := test.game(&Player{}, &Player{})
+		for _, m := range test.moves {
+			if err := game.ApplyMove(game.ToMove(), m); err != nil {
+				t.Fatalf("%s: ApplyMove(%q) error = %v", test.name, m, err)
+			}
+		}
+
+		for seed := int64(0); seed < 10; seed++ {
+			a := NewAIChooser(rand.New(rand.NewSource(seed)))
+			got, err := a.ChooseMove(context.Background(), game)
+			if err != nil {
+				t.Fatalf("%s: ChooseMove() error = %v", test.name, err)
+			}
+			if !slices.Contains(test.want, got) {
+				t.Errorf("%s: ChooseMove() with seed %d = %q, want one of %v", test.name, seed, got, test.want)
+			}
+		}
+	}
+}
+
+func TestPlayerChooseMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(p *Player)
+		wantErr bool
+	}{
+		{
+			// A player with no strategy can not choose.
+			name:    "unset",
+			setup:   func(p *Player) {},
+			wantErr: true,
+		},
+		{
+			name:  "computer",
+			setup: func(p *Player) { p.SetComputer() },
+		},
+		{
+			name:  "default AI",
+			setup: func(p *Player) { p.SetAI(nil) },
+		},
+		{
+			name: "chooser",
+			setup: func(p *Player) {
+				p.SetMoveChooser(NewRandomChooser(rand.New(rand.NewSource(1))))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p1, p2 := &Player{}, &Player{}
+		test.setup(p1)
+		game := TicTacToe(p1, p2)
+
+		got, err := p1.ChooseMove(context.Background(), game)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: ChooseMove() error = %v, want error %v", test.name, err, test.wantErr)
+			continue
+		}
+		if err == nil && !slices.Contains(game.PotentialMoves(), got) {
+			t.Errorf("%s: ChooseMove() = %q, want one of %v", test.name, got, game.PotentialMoves())
+		}
+	}
+}
+
+func TestChoosersConnect6(t *testing.T) {
+	black, white := &Player{}, &Player{}
+	game := Connect6(black, white)
+	for _, m := range []string{"j10", "a1+a2", "j11+j12", "b1+b2", "j13+j14"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+
+	// White must block black's five in a row.
+	a := NewAIChooser(rand.New(rand.NewSource(1)))
+	got, err := a.ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("AIChooser.ChooseMove() error = %v", err)
+	}
+	if got != "j9" && got != "j15" {
+		t.Errorf("AIChooser.ChooseMove() = %q, want %q or %q", got, "j9", "j15")
+	}
+
+	// A human may type both stones of the turn in either order.
+	h := NewHumanChooser(strings.NewReader("s19+c3\n"), io.Discard)
+	got, err = h.ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("HumanChooser.ChooseMove() error = %v", err)
+	}
+	if want := "s19+c3"; got != want {
+		t.Errorf("HumanChooser.ChooseMove() = %q, want %q", got, want)
+	}
+}
@@ -3,6 +3,7 @@ package mnkgame
 import (
 	"fmt"
 	"slices"
+	"strings"
 )
 
 // Coord is the coordinates of a cell in a game using a Top-Left origin.
@@ -10,17 +11,32 @@ type Coord struct {
 	Row int
 	Col int
 
+	// Layer is the layer of the cell on a three dimensional board. It is
+	// always 0 on a flat board.
+	Layer int
+
 	// TODO(rsned): Consiser adding a connections to other Coord for
 	// values that have specific ways they can be reached from other
 	// Coords.
 }
 
 func (c Coord) String() string {
+	if c.Layer != 0 {
+		return c.layered()
+	}
 	return fmt.Sprintf("(%d,%d)", c.Row, c.Col)
 }
 
+// layered returns the coord written with its layer, even the first layer.
+func (c Coord) layered() string {
+	return fmt.Sprintf("(%d,%d,%d)", c.Layer, c.Row, c.Col)
+}
+
 // less is used for sorting.
 func (c Coord) less(other Coord) bool {
+	if c.Layer != other.Layer {
+		return c.Layer < other.Layer
+	}
 	if c.Row <= other.Row {
 		return c.Col < other.Col
 	}
@@ -29,7 +45,7 @@ func (c Coord) less(other Coord) bool {
 
 // equals is used in comparisons.
 func (c Coord) equals(other Coord) bool {
-	return c.Row == other.Row && c.Col == other.Col
+	return c.Layer == other.Layer && c.Row == other.Row && c.Col == other.Col
 }
 
 // coordEqual is the non type-bound check for equality.
@@ -39,6 +55,13 @@ func coordEqual(a, b Coord) bool {
 
 // coordCompare is the cmp.Compare func for Coord types.
 func coordCompare(a, b Coord) int {
+	if a.Layer != b.Layer {
+		if a.Layer < b.Layer {
+			return -1
+		}
+		return 1
+	}
+
 	if a.Row < b.Row {
 		return -1
 	}
@@ -60,6 +83,22 @@ func coordCompare(a, b Coord) int {
 // Coords is a slice of Coord values.
 type Coords []Coord
 
+// String returns the coords as a list. If any of them is on a layer other
+// than the first, all of them are written with their layer so that a line
+// through the layers reads the same from end to end.
+func (c Coords) String() string {
+	layered := slices.ContainsFunc(c, func(v Coord) bool { return v.Layer != 0 })
+	parts := make([]string, len(c))
+	for i, v := range c {
+		if layered {
+			parts[i] = v.layered()
+		} else {
+			parts[i] = v.String()
+		}
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
 // Add attempts to add the given value, skipping if the value already is in the slice.
 func (c *Coords) Add(coord Coord) {
 	for _, v := range *c {
@@ -0,0 +1,5 @@
+/*
+Package engine implements computer players that search the positions of an
+mnkgame.MNKGame to pick their moves.
+*/
+package engine
@@ -0,0 +1,245 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/rsned/games/mnkgame"
+)
+
+const (
+	// defaultPlayouts is used when neither a playout count or a time budget
+	// is given to the MCTS engine.
+	defaultPlayouts = 1000
+)
+
+// RolloutPolicy picks the next move to play during a random playout from
+// the set of available moves. A policy may try moves on the game, but must
+// undo them before returning.
+type RolloutPolicy func(rng *rand.Rand, game *mnkgame.MNKGame, moves []string) string
+
+// RandomRollout picks uniformly from the available moves.
+func RandomRollout(rng *rand.Rand, game *mnkgame.MNKGame, moves []string) string {
+	return moves[rng.Intn(len(moves))]
+}
+
+// WinningRollout plays an immediately winning move when there is one, and
+// otherwise picks at random. It makes the playouts much more realistic but
+// tries every available move at every step, so it is best on smaller boards.
+func WinningRollout(rng *rand.Rand, game *mnkgame.MNKGame, moves []string) string {
+	me := game.ToMove()
+	for _, move := range moves {
+		if game.ApplyMove(me, move) != nil {
+			continue
+		}
+		won := game.Outcome(me) == mnkgame.OutcomeWin
+		game.Undo()
+		if won {
+			return move
+		}
+	}
+	return moves[rng.Intn(len(moves))]
+}
+
+// MCTS is a Monte Carlo Tree Search engine using the UCT selection rule.
+// Rather than trying to search every position, it plays out many random
+// games and concentrates on the moves that win most often, which makes it
+// usable on large boards such as Gomoku.
+//
+// MCTS implements mnkgame.MoveChooser.
+type MCTS struct {
+	// Playouts is the number of games to play out for each move. A value of
+	// 0 means there is no limit other than the TimeBudget.
+	Playouts int
+
+	// TimeBudget is how long to search for each move. A value of 0 means
+	// there is no limit other than the number of Playouts.
+	TimeBudget time.Duration
+
+	// Exploration is the UCT exploration constant. Larger values spend more
+	// time on moves that have been tried less. If 0, √2 is used.
+	Exploration float64
+
+	// Rollout picks the moves during playouts. If nil, RandomRollout is used.
+	Rollout RolloutPolicy
+
+	// Seed seeds the random number generator for each search, so that with
+	// a playout count and no time budget the results are reproducible.
+	Seed int64
+}
+
+// NewMCTS returns an MCTS engine that runs the given number of playouts per
+// move using random rollouts and the given seed.
+func NewMCTS(playouts int, seed int64) *MCTS {
+	return &MCTS{
+		Playouts: playouts,
+		Seed:     seed,
+	}
+}
+
+// mctsNode is one position in the search tree.
+type mctsNode struct {
+	parent   *mctsNode
+	children []*mctsNode
+
+	// move is the move that led to this node and player is who made it.
+	move   string
+	player *mnkgame.Player
+
+	// untried are the moves from this position not yet expanded.
+	untried []string
+
+	visits int
+	// wins is the total result for player over all the visits, counting 1
+	// for a win and 0.5 for a draw.
+	wins float64
+}
+
+// uct returns the upper confidence bound of this node as seen by its parent.
+func (n *mctsNode) uct(c float64) float64 {
+	if n.visits == 0 {
+		return math.Inf(1)
+	}
+	return n.wins/float64(n.visits) +
+		c*math.Sqrt(math.Log(float64(n.parent.visits))/float64(n.visits))
+}
+
+// ChooseMove returns the best move found for the player whose turn it is.
+func (m *MCTS) ChooseMove(ctx context.Context, game *mnkgame.MNKGame) (string, error) {
+	move, _, err := m.Search(ctx, game)
+	return move, err
+}
+
+// Search runs the playouts from the current position and returns the most
+// visited move along with the fraction of its playouts that the player
+// whose turn it is went on to win (draws counting as half a win).
+//
+// If the context is canceled after at least one playout, the best move so
+// far is returned.
+func (m *MCTS) Search(ctx context.Context, game *mnkgame.MNKGame) (string, float64, error) {
+	moves := game.PotentialMoves()
+	if len(moves) == 0 {
+		return "", 0, fmt.Errorf("No moves available")
+	}
+
+	playouts := m.Playouts
+	if playouts == 0 && m.TimeBudget == 0 {
+		playouts = defaultPlayouts
+	}
+	var deadline time.Time
+	if m.TimeBudget > 0 {
+		deadline = time.Now().Add(m.TimeBudget)
+	}
+	c := m.Exploration
+	if c == 0 {
+		c = math.Sqrt2
+	}
+	rollout := m.Rollout
+	if rollout == nil {
+		rollout = RandomRollout
+	}
+	rng := rand.New(rand.NewSource(m.Seed))
+
+	// Each playout is made on a copy of the game and then taken back.
+	g := game.Clone()
+	root := &mctsNode{untried: moves}
+	for i := 0; playouts == 0 || i < playouts; i++ {
+		if err := ctx.Err(); err != nil {
+			if root.visits == 0 {
+				return "", 0, err
+			}
+			break
+		}
+		if i > 0 && !deadline.IsZero() && time.Now().After(deadline) {
+			break
+		}
+
+		n := root
+		played := g.MovesPlayed()
+
+		// Selection: walk down the fully expanded nodes.
+		for len(n.untried) == 0 && len(n.children) > 0 {
+			best := n.children[0]
+			for _, child := range n.children[1:] {
+				if child.uct(c) > best.uct(c) {
+					best = child
+				}
+			}
+			n = best
+			g.ApplyMove(n.player, n.move)
+		}
+
+		// Expansion: add one untried move as a new child.
+		if len(n.untried) > 0 && g.Outcome(g.ToMove()) == mnkgame.OutcomeIncomplete {
+			k := rng.Intn(len(n.untried))
+			move := n.untried[k]
+			n.untried[k] = n.untried[len(n.untried)-1]
+			n.untried = n.untried[:len(n.untried)-1]
+
+			player := g.ToMove()
+			if err := g.ApplyMove(player, move); err == nil {
+				child := &mctsNode{
+					parent: n,
+					move:   move,
+					player: player,
+				}
+				if g.Outcome(player) == mnkgame.OutcomeIncomplete {
+					child.untried = g.PotentialMoves()
+				}
+				n.children = append(n.children, child)
+				n = child
+			}
+		}
+
+		// Simulation: play the game out to the end.
+		for g.Outcome(g.ToMove()) == mnkgame.OutcomeIncomplete {
+			moves := g.PotentialMoves()
+			if len(moves) == 0 {
+				break
+			}
+			player := g.ToMove()
+			if err := g.ApplyMove(player, rollout(rng, g, moves)); err != nil {
+				break
+			}
+		}
+
+		// Backpropagation: credit each node for its player's result.
+		for ; n != nil; n = n.parent {
+			n.visits++
+			if n.player != nil {
+				n.wins += result(g.Outcome(n.player))
+			}
+		}
+
+		for g.MovesPlayed() > played {
+			g.Undo()
+		}
+	}
+
+	if len(root.children) == 0 {
+		return "", 0, fmt.Errorf("No legal moves available")
+	}
+
+	best := root.children[0]
+	for _, child := range root.children[1:] {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+	return best.move, best.wins / float64(best.visits), nil
+}
+
+// result converts an outcome into the value credited to a node.
+func result(o mnkgame.Outcome) float64 {
+	switch o {
+	case mnkgame.OutcomeWin:
+		return 1
+	case mnkgame.OutcomeDraw:
+		return 0.5
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rsned/games/mnkgame"
+)
+
+func TestMCTSSearch(t *testing.T) {
+	tests := []struct {
+		name     string
+		moves    []string
+		wantMove string
+	}{
+		{
+			// Player 1 wins immediately on the top row.
+			name:     "win in one",
+			moves:    []string{"TL", "CL", "TC", "CC"},
+			wantMove: "TR",
+		},
+		{
+			// Player 2 has to block the diagonal.
+			name:     "forced block",
+			moves:    []string{"TL", "TC", "CC"},
+			wantMove: "BR",
+		},
+	}
+
+	for _, test := range tests {
+		game := mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2)
+		for _, m := range test.moves {
+			if err := game.ApplyMove(game.ToMove(), m); err != nil {
+				t.Fatalf("%s: ApplyMove(%q) error = %v", test.name, m, err)
+			}
+		}
+
+		got, _, err := NewMCTS(2000, 1).Search(context.Background(), game)
+		if err != nil {
+			t.Errorf("%s: Search() error = %v", test.name, err)
+			continue
+		}
+		if got != test.wantMove {
+			t.Errorf("%s: Search() = %q, want %q", test.name, got, test.wantMove)
+		}
+	}
+}
+
+func TestMCTSReproducible(t *testing.T) {
+	game := mnkgame.NewMNKGame("Gomoku", 15, 15, 5, nil, mnkgame.Player1, mnkgame.Player2)
+	for _, m := range []string{"8,8", "7,7", "8,9"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+
+	m := &MCTS{Playouts: 50, Seed: 42, Rollout: RandomRollout}
+	first, firstScore, err := m.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if !slices.Contains(game.PotentialMoves(), first) {
+		t.Errorf("Search() = %q, not a potential move", first)
+	}
+
+	second, secondScore, err := m.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if first != second || firstScore != secondScore {
+		t.Errorf("Search() with the same seed = (%q, %v) then (%q, %v), want the same",
+			first, firstScore, second, secondScore)
+	}
+}
+
+func TestMCTSTimeBudget(t *testing.T) {
+	game := mnkgame.NewMNKGame("Gomoku", 15, 15, 5, nil, mnkgame.Player1, mnkgame.Player2)
+	m := &MCTS{TimeBudget: 50 * time.Millisecond}
+
+	start := time.Now()
+	if _, err := m.ChooseMove(context.Background(), game); err != nil {
+		t.Fatalf("ChooseMove() error = %v", err)
+	}
+	// Allow for one slow playout past the deadline.
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("ChooseMove() with a 50ms budget took %v", elapsed)
+	}
+}
+
+func TestMCTSNeverLoses(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := &MCTS{Playouts: 2000, Seed: 1, Rollout: WinningRollout}
+	for i := 0; i < 4; i++ {
+		game := mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2)
+		random := mnkgame.NewRandomChooser(rng)
+
+		if i%2 == 0 {
+			if o1, _ := playGame(t, game, m, random); o1 == mnkgame.OutcomeLoss {
+				t.Errorf("game %d: mcts as player 1 lost", i)
+			}
+		} else {
+			if _, o2 := playGame(t, game, random, m); o2 == mnkgame.OutcomeLoss {
+				t.Errorf("game %d: mcts as player 2 lost", i)
+			}
+		}
+	}
+}
+
+func TestMCTSCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	game := mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2)
+	if _, err := NewMCTS(100, 1).ChooseMove(ctx, game); err == nil {
+		t.Errorf("ChooseMove() with a canceled context = nil error, want error")
+	}
+}
@@ -0,0 +1,215 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/rsned/games/mnkgame"
+)
+
+const (
+	// winScore is the score of a won position. Wins found sooner are scored
+	// higher by subtracting the number of moves needed to reach them.
+	winScore = 1000000
+)
+
+// Minimax searches the game tree using negamax with alpha-beta pruning.
+// With no depth limit it plays small boards such as Tic-Tac-Toe perfectly,
+// but the search grows far too quickly to be used on large boards.
+//
+// Minimax implements mnkgame.MoveChooser.
+type Minimax struct {
+	// MaxDepth is the number of moves to look ahead. A value of 0 means
+	// search until the end of the game.
+	MaxDepth int
+}
+
+// NewMinimax returns a minimax engine that looks maxDepth moves ahead.
+func NewMinimax(maxDepth int) *Minimax {
+	return &Minimax{MaxDepth: maxDepth}
+}
+
+// ChooseMove returns the best move for the player whose turn it is.
+func (m *Minimax) ChooseMove(ctx context.Context, game *mnkgame.MNKGame) (string, error) {
+	move, _, err := m.Search(ctx, game)
+	return move, err
+}
+
+// bound records how a cached score relates to the true score of a position.
+type bound int
+
+const (
+	boundExact bound = iota
+	boundLower
+	boundUpper
+)
+
+// ttEntry is a cached search result for one position.
+type ttEntry struct {
+	score int
+	bound bound
+	depth int // The number of moves searched below the position.
+}
+
+// Search returns the best move for the player whose turn it is along with
+// its score. Positive scores are good for that player, negative scores are
+// good for the opponent, and 0 is a draw or an undecided position.
+//
+// When several moves share the best score, the first in PotentialMoves order
+// is returned so the results are repeatable.
+func (m *Minimax) Search(ctx context.Context, game *mnkgame.MNKGame) (string, int, error) {
+	moves := game.PotentialMoves()
+	if len(moves) == 0 {
+		return "", 0, fmt.Errorf("No moves available")
+	}
+
+	// Positions are often reached by more than one order of moves, so cache
+	// the results for this search.
+	tt := map[string]ttEntry{}
+
+	// Search a copy so moves can be played and taken back without changing
+	// the callers game.
+	g := game.Clone()
+	me := g.ToMove()
+	bestMove := ""
+	bestScore := math.MinInt
+	alpha, beta := -math.MaxInt, math.MaxInt
+	for _, move := range moves {
+		if err := g.ApplyMove(me, move); err != nil {
+			continue
+		}
+		score, err := m.after(ctx, g, tt, me, 1, alpha, beta)
+		g.Undo()
+		if err != nil {
+			return "", 0, err
+		}
+		if score > bestScore {
+			bestMove, bestScore = move, score
+		}
+		alpha = max(alpha, score)
+	}
+
+	if bestMove == "" {
+		return "", 0, fmt.Errorf("No legal moves available")
+	}
+	return bestMove, bestScore, nil
+}
+
+// negamax returns the score of the game for the player whose turn it is,
+// ply moves below the root of the search.
+func (m *Minimax) negamax(ctx context.Context, g *mnkgame.MNKGame, tt map[string]ttEntry, ply, alpha, beta int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	me := g.ToMove()
+	if o := g.Outcome(me); o != mnkgame.OutcomeIncomplete {
+		return terminalScore(o, ply), nil
+	}
+
+	depth := math.MaxInt
+	if m.MaxDepth > 0 {
+		depth = m.MaxDepth - ply
+		if depth <= 0 {
+			return 0, nil
+		}
+	}
+
+	key := g.PositionKey()
+	if e, ok := tt[key]; ok && e.depth >= depth {
+		score := fromTT(e.score, ply)
+		switch {
+		case e.bound == boundExact:
+			return score, nil
+		case e.bound == boundLower && score >= beta:
+			return score, nil
+		case e.bound == boundUpper && score <= alpha:
+			return score, nil
+		}
+	}
+
+	origAlpha := alpha
+	best := -math.MaxInt
+	for _, move := range g.PotentialMoves() {
+		if err := g.ApplyMove(me, move); err != nil {
+			continue
+		}
+		score, err := m.after(ctx, g, tt, me, ply+1, alpha, beta)
+		g.Undo()
+		if err != nil {
+			return 0, err
+		}
+
+		best = max(best, score)
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if best == -math.MaxInt {
+		// No move could be applied, treat it as a draw.
+		return 0, nil
+	}
+
+	e := ttEntry{score: toTT(best, ply), bound: boundExact, depth: depth}
+	switch {
+	case best <= origAlpha:
+		e.bound = boundUpper
+	case best >= beta:
+		e.bound = boundLower
+	}
+	tt[key] = e
+
+	return best, nil
+}
+
+// after returns the score for player me of the position after their move,
+// ply moves below the root of the search. Usually the opponent is to move
+// and their score is negated, but in games such as Connect6 the same player
+// may move again.
+func (m *Minimax) after(ctx context.Context, g *mnkgame.MNKGame, tt map[string]ttEntry, me *mnkgame.Player, ply, alpha, beta int) (int, error) {
+	if g.ToMove() == me {
+		return m.negamax(ctx, g, tt, ply, alpha, beta)
+	}
+	score, err := m.negamax(ctx, g, tt, ply, -beta, -alpha)
+	return -score, err
+}
+
+// toTT converts a score from being relative to the root of the search to
+// being relative to the position, so a cached win or loss can be reused when
+// the position is reached at a different ply.
+func toTT(score, ply int) int {
+	switch {
+	case score > winScore/2:
+		return score + ply
+	case score < -winScore/2:
+		return score - ply
+	}
+	return score
+}
+
+// fromTT is the inverse of toTT.
+func fromTT(score, ply int) int {
+	switch {
+	case score > winScore/2:
+		return score - ply
+	case score < -winScore/2:
+		return score + ply
+	}
+	return score
+}
+
+// terminalScore converts a finished games outcome into a score, preferring
+// quicker wins and slower losses.
+func terminalScore(o mnkgame.Outcome, ply int) int {
+	switch o {
+	case mnkgame.OutcomeWin:
+		return winScore - ply
+	case mnkgame.OutcomeLoss:
+		return ply - winScore
+	default:
+		return 0
+	}
+}
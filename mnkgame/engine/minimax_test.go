@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/rsned/games/mnkgame"
+)
+
+// playGame plays the game to completion with the given choosers and returns
+// the outcomes for each player.
+func playGame(t *testing.T, game *mnkgame.MNKGame, c1, c2 mnkgame.MoveChooser) (mnkgame.Outcome, mnkgame.Outcome) {
+	t.Helper()
+	p1, _ := game.Players()
+	for {
+		if o1, o2 := game.Outcomes(); o1 != mnkgame.OutcomeIncomplete {
+			return o1, o2
+		}
+
+		player := game.ToMove()
+		chooser := c2
+		if player == p1 {
+			chooser = c1
+		}
+		move, err := chooser.ChooseMove(context.Background(), game)
+		if err != nil {
+			t.Fatalf("ChooseMove() error = %v", err)
+		}
+		if err := game.ApplyMove(player, move); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", move, err)
+		}
+	}
+}
+
+func TestMinimaxSearch(t *testing.T) {
+	tests := []struct {
+		name      string
+		game      *mnkgame.MNKGame
+		moves     []string
+		wantMove  string
+		wantScore int
+	}{
+		{
+			// Perfect play from the start of Tic-Tac-Toe is a draw.
+			name:      "tic-tac-toe opening",
+			game:      mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2),
+			wantScore: 0,
+		},
+		{
+			// Player 1 wins immediately on the top row.
+			name:      "tic-tac-toe win in one",
+			game:      mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2),
+			moves:     []string{"TL", "CL", "TC", "CC"},
+			wantMove:  "TR",
+			wantScore: winScore - 1,
+		},
+		{
+			// Player 2 has to block the diagonal, but player 1 then
+			// forks, so the block only delays the loss.
+			name:      "tic-tac-toe forced block",
+			game:      mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2),
+			moves:     []string{"TL", "TC", "CC"},
+			wantMove:  "BR",
+			wantScore: 4 - winScore,
+		},
+		{
+			// Two stones a turn, so player 1 completes the top row this
+			// turn before player 2 can finish the bottom row.
+			name:      "two stones a turn",
+			game:      mnkgame.NewMNKGame("4x4x4", 4, 4, 4, mnkgame.Connect6Rules, mnkgame.Player1, mnkgame.Player2),
+			moves:     []string{"1,1", "4,1", "4,2", "1,2", "2,4", "4,3", "3,3"},
+			wantScore: winScore - 2,
+		},
+		{
+			// 4x4 with 3 in a row is a win for the first player.
+			name:      "4x4x3 opening",
+			game:      mnkgame.NewMNKGame("4x4x3", 4, 4, 3, nil, mnkgame.Player1, mnkgame.Player2),
+			wantScore: winScore - 5,
+		},
+	}
+
+	for _, test := range tests {
+		for _, m := range test.moves {
+			if err := test.game.ApplyMove(test.game.ToMove(), m); err != nil {
+				t.Fatalf("%s: ApplyMove(%q) error = %v", test.name, m, err)
+			}
+		}
+
+		gotMove, gotScore, err := NewMinimax(0).Search(context.Background(), test.game)
+		if err != nil {
+			t.Errorf("%s: Search() error = %v", test.name, err)
+			continue
+		}
+		if test.wantMove != "" && gotMove != test.wantMove {
+			t.Errorf("%s: Search() move = %q, want %q", test.name, gotMove, test.wantMove)
+		}
+		if gotScore != test.wantScore {
+			t.Errorf("%s: Search() score = %d, want %d", test.name, gotScore, test.wantScore)
+		}
+	}
+}
+
+func TestMinimaxNeverLoses(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		game := mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2)
+		random := mnkgame.NewRandomChooser(rng)
+
+		if i%2 == 0 {
+			if o1, _ := playGame(t, game, NewMinimax(0), random); o1 == mnkgame.OutcomeLoss {
+				t.Errorf("game %d: minimax as player 1 lost", i)
+			}
+		} else {
+			if _, o2 := playGame(t, game, random, NewMinimax(0)); o2 == mnkgame.OutcomeLoss {
+				t.Errorf("game %d: minimax as player 2 lost", i)
+			}
+		}
+	}
+}
+
+func TestMinimaxCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	game := mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2)
+	if _, err := NewMinimax(0).ChooseMove(ctx, game); err == nil {
+		t.Errorf("ChooseMove() with a canceled context = nil error, want error")
+	}
+}
+
+func TestMinimaxNeverLosesMisere(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		game := mnkgame.TicTacToe(mnkgame.Player1, mnkgame.Player2)
+		game.SetMisere(true)
+		random := mnkgame.NewRandomChooser(rng)
+
+		if i%2 == 0 {
+			if o1, _ := playGame(t, game, NewMinimax(0), random); o1 == mnkgame.OutcomeLoss {
+				t.Errorf("game %d: minimax as player 1 lost", i)
+			}
+		} else {
+			if _, o2 := playGame(t, game, random, NewMinimax(0)); o2 == mnkgame.OutcomeLoss {
+				t.Errorf("game %d: minimax as player 2 lost", i)
+			}
+		}
+	}
+}
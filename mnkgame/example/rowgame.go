@@ -1,58 +1,70 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"math/rand"
+	"os"
 
 	"github.com/rsned/games/mnkgame"
+	"github.com/rsned/games/mnkgame/engine"
 )
 
 func main() {
 	playerN := readInput("Do you wish to be player 1 or 2?", []string{"1", "2"})
-	var player1Play, player2Play playFunc
+	level := readInput("Choose a difficulty: easy, medium, or hard?",
+		[]string{"easy", "medium", "hard"})
+
 	player1 := mnkgame.Player1
 	player2 := mnkgame.Player2
 
-	if playerN == "1" {
-		player1.SetHuman()
-		player1Play = humanPlayer
-		player2.SetComputer()
-		player2Play = randomPlayer
-	} else {
-		player1.SetComputer()
-		player1Play = randomPlayer
-		player2.SetHuman()
-		player2Play = humanPlayer
+	human, computer := player1, player2
+	if playerN == "2" {
+		human, computer = player2, player1
+	}
+
+	human.SetHuman()
+	switch level {
+	case "easy":
+		computer.SetComputer()
+	case "medium":
+		computer.SetAI(nil)
+	case "hard":
+		computer.SetAI(engine.NewMinimax(0))
 	}
 
 	game := mnkgame.TicTacToe(player1, player2)
-	var move string
+	renderer := mnkgame.NewANSIRenderer(mnkgame.DefaultBoardOptions())
+	ctx := context.Background()
 
-	// TODO(rsned): There's lots of repetition here, refactor player some more
-	// and the Outcome method to make it easier for player to be more abstract
-	// and this loop and above setup simpler.
 	for {
-		// Player 1
-		fmt.Printf("\n%s\n", game.RenderBoard())
-		move = player1Play(player1, game)
-		game.ApplyMove(player1, move)
-
-		if p1, _ := game.Outcome(); p1 != mnkgame.OutcomeIncomplete {
-			fmt.Printf("\n%s\n", game.RenderBoard())
-			fmt.Printf("Game Over. %s Wins.\n", player1.String())
-			break
+		player := game.ToMove()
+		fmt.Println()
+		game.Render(os.Stdout, renderer)
+
+		move, err := player.ChooseMove(ctx, game)
+		if err != nil {
+			fmt.Printf("%s could not choose a move: %v\n", player, err)
+			return
+		}
+		if err := game.ApplyMove(player, move); err != nil {
+			fmt.Printf("%s plays %s: %v\n", player, move, err)
+			continue
 		}
+		fmt.Printf("%s plays %s\n", player, move)
 
-		// Player 2
-		fmt.Printf("\n%s\n", game.RenderBoard())
-		move = player2Play(player2, game)
-		game.ApplyMove(player2, move)
+		outcome := game.Outcome(player)
+		if outcome == mnkgame.OutcomeIncomplete {
+			continue
+		}
 
-		if _, p2 := game.Outcome(); p2 != mnkgame.OutcomeIncomplete {
-			fmt.Printf("\n%s\n", game.RenderBoard())
-			fmt.Printf("Game Over. %s Wins.\n", player2)
-			break
+		fmt.Println()
+		game.Render(os.Stdout, renderer)
+		if outcome == mnkgame.OutcomeWin {
+			fmt.Printf("Game Over. %s Wins.\n", player)
+		} else {
+			fmt.Println("Game Over. It's a draw.")
 		}
+		break
 	}
 }
 
@@ -79,22 +91,3 @@ func readInput(prompt string, valid []string) string {
 		fmt.Printf("Invalid entry %q, please try again.\n", entry)
 	}
 }
-
-type playFunc func(*mnkgame.Player, *mnkgame.MNKGame) string
-
-func humanPlayer(player *mnkgame.Player, game *mnkgame.MNKGame) string {
-	moves := game.PotentialMoves()
-	// TODO(rsned): As a feature request, differentiate between bad
-	// text input (e.g., "TR" vs "2qq123uu*)AS", and position already
-	// taken (e.g. player 2 enters the postion of where player 1 just
-	// played.)
-	move := readInput(fmt.Sprintf("Select square: %+v", moves), moves)
-	return move
-}
-
-func randomPlayer(player *mnkgame.Player, games *mnkgame.MNKGame) string {
-	moves := games.PotentialMoves()
-	move := moves[rand.Intn(len(moves))]
-	fmt.Printf("%s plays %s\n", player, move)
-	return move
-}
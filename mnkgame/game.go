@@ -1,5 +1,14 @@
 package mnkgame
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
 // Outcome is an enumeration of the various possible states of a game.
 type Outcome int
 
@@ -39,57 +48,466 @@ type MNKGame struct {
 	player1 *Player
 	player2 *Player
 
+	// marker1 and marker2 are the markers player 1 and player 2 place in
+	// this game. The game keeps them rather than the players so a player
+	// can be in several games at once, each with its own markers.
+	marker1 Marker
+	marker2 Marker
+
 	board *Board
+
+	// boards are the sub-boards of a game played on several boards, such as
+	// Notakto. The cells of the main board then track the state of each of
+	// the sub-boards.
+	boards []*Board
+
+	// rules decides which moves are legal, how they are applied, and
+	// the outcome of the game.
+	rules Rules
+
+	// history is every move played so far in order, and undone are the
+	// moves taken back by Undo that may be replayed by Redo, most recently
+	// undone last.
+	history []Move
+	undone  []Move
+
+	// misere inverts the goal of the game so that the player who completes
+	// a line loses instead of winning.
+	misere bool
+}
+
+// NewMNKGame returns a new game with the given name on a rows x cols board
+// where size markers in a row are needed to win. If rules is nil, the
+// PlacementRules are used.
+//
+// The players place their own markers. If they have the same marker, or one
+// has none, a different one is used in this game so the players can always
+// be told apart.
+func NewMNKGame(name string, rows, cols, size int, rules Rules, p1, p2 *Player) *MNKGame {
+	if rules == nil {
+		rules = PlacementRules
+	}
+
+	g := &MNKGame{
+		name: name,
+		rows: rows,
+		cols: cols,
+		size: size,
+
+		player1: p1,
+		player2: p2,
+
+		rules: rules,
+	}
+	g.board = newBoard(g.rows, g.cols, g.size)
+	g.setMarkers(p1.marker, p2.marker)
+
+	return g
+}
+
+// playerMarkers are the markers, in order of preference, given to a player
+// who has no marker or the same marker as the other player.
+var playerMarkers = []Marker{MarkerX, MarkerWhiteStone, MarkerBlackStone}
+
+// setMarkers sets the markers the players place in this game. An empty
+// marker, or a second marker the same as the first, is replaced.
+func (t *MNKGame) setMarkers(m1, m2 Marker) {
+	other := func(m Marker) Marker {
+		for _, pm := range playerMarkers {
+			if pm != m {
+				return pm
+			}
+		}
+		return MarkerEmpty
+	}
+	if m1 == MarkerEmpty {
+		m1 = other(m2)
+	}
+	if m2 == MarkerEmpty || m2 == m1 {
+		m2 = other(m1)
+	}
+	t.marker1, t.marker2 = m1, m2
+}
+
+// Marker returns the marker player p places in this game, or MarkerEmpty if
+// p is not playing in this game.
+func (t *MNKGame) Marker(p *Player) Marker {
+	switch p {
+	case t.player1:
+		return t.marker1
+	case t.player2:
+		return t.marker2
+	default:
+		return MarkerEmpty
+	}
 }
 
 // RenderBoard returns a string representation of the current board state.
 func (t *MNKGame) RenderBoard() string {
-	return t.board.String()
+	var buf bytes.Buffer
+	t.Render(&buf, defaultTextRenderer)
+	return buf.String()
+}
+
+// RenderWithOptions returns a text representation of the current board state
+// laid out using the given options.
+func (t *MNKGame) RenderWithOptions(opts BoardOptions) string {
+	var buf bytes.Buffer
+	if len(t.boards) == 0 {
+		defaultTextRenderer.renderWithOptions(&buf, t.Board(), opts, nil)
+	} else {
+		t.Render(&buf, NewTextRenderer(opts))
+	}
+	return buf.String()
 }
 
-// OpenPositions returns a list of all the open positions on the board.
+// Render draws the current board using the given renderer. For games played
+// on several boards, the sub-boards are drawn instead if the renderer can
+// draw more than one board. They are laid out in the same shape as the main
+// board, each sub-board in the place of its cell.
+func (t *MNKGame) Render(w io.Writer, r Renderer) error {
+	mr, ok := r.(multiRenderer)
+	if !ok || len(t.boards) == 0 {
+		return r.Render(w, t.Board())
+	}
+	return mr.RenderBoards(w, t.boardRows())
+}
+
+// boardRows returns the views of the sub-boards laid out in rows the width
+// of the main board.
+func (t *MNKGame) boardRows() [][]BoardView {
+	var rows [][]BoardView
+	views := t.Boards()
+	for len(views) > 0 {
+		n := min(t.board.cols, len(views))
+		rows = append(rows, views[:n])
+		views = views[n:]
+	}
+	return rows
+}
+
+// Board returns a read-only view of the games board.
+func (t *MNKGame) Board() BoardView {
+	return gameView{Board: t.board, game: t}
+}
+
+// Boards returns read-only views of the sub-boards of a game played on
+// several boards, or nil if the game has only the one board.
+func (t *MNKGame) Boards() []BoardView {
+	var views []BoardView
+	for _, b := range t.boards {
+		views = append(views, gameView{Board: b, game: t})
+	}
+	return views
+}
+
+// allBoards returns the main board followed by any sub-boards, in the order
+// of their indexes.
+func (t *MNKGame) allBoards() []*Board {
+	return append([]*Board{t.board}, t.boards...)
+}
+
+// addBoards adds n sub-boards to the game with the given dimensions and
+// target size.
+func (t *MNKGame) addBoards(n, rows, cols, size int) {
+	for i := 0; i < n; i++ {
+		b := newBoard(rows, cols, size)
+		b.index = len(t.boards) + 1
+		t.boards = append(t.boards, b)
+	}
+}
+
+// gameView is the view of a games board, which also knows the last move
+// played so renderers can highlight it.
+type gameView struct {
+	*Board
+	game *MNKGame
+}
+
+// WinningLine returns the cells of the line that won the game, if it is on
+// this board. Lines made before the game is over, such as a mill in Nine
+// Mens Morris, are not winning lines. If the games rules find lines across
+// several boards, only the cells of the line on this board are returned.
+func (v gameView) WinningLine() Coords {
+	_, ok := v.game.rules.(winningLiner)
+	if !ok && v.index > 0 {
+		// Each sub-board is a game of its own, such as in ultimate
+		// tic-tac-toe, with a line once it is decided.
+		return v.Board.WinningLine()
+	}
+
+	line := v.game.WinningLine()
+	if !ok || len(v.game.boards) == 0 {
+		return line
+	}
+
+	// Layer l of the line is on sub-board l+1.
+	var onBoard Coords
+	for _, c := range line {
+		if c.Layer+1 == v.index {
+			onBoard = append(onBoard, Coord{Row: c.Row, Col: c.Col})
+		}
+	}
+	return onBoard
+}
+
+// LastMove returns the cell of the most recent move, or false if no moves
+// have been played or the last move was on another board.
+func (v gameView) LastMove() (Coord, bool) {
+	if len(v.game.history) == 0 {
+		return Coord{}, false
+	}
+	m := v.game.history[len(v.game.history)-1]
+	if m.Board != v.index {
+		return Coord{}, false
+	}
+	return m.Coord, true
+}
+
+// OpenPositions returns a list of all the open positions on the main board.
+// These are not always moves, such as in Connect 4 where a move is a column,
+// or in games played on several boards where the main board only tracks the
+// state of the sub-boards. Use PotentialMoves for the moves the rules allow.
 func (t *MNKGame) OpenPositions() []string {
 	return t.board.OpenPositions()
 }
 
-// PotentialMoves returns a list of potential moves available.
-//
-// TODO(rsned): Augment this to support games like Nine Mens Morris and others
-// that allow markers to move after they have been played.
+// PotentialMoves returns a list of potential moves available under the
+// games rules.
 func (t *MNKGame) PotentialMoves() []string {
-	return t.board.OpenPositions()
+	return t.rules.LegalMoves(t)
 }
 
 // ApplyMove attempts to apply the users choice of move. If any errors occur,
 // such as an illegal move, the error will be non-nil.
+//
+// A successful move is added to the history and clears any moves waiting to
+// be redone.
 func (t *MNKGame) ApplyMove(player *Player, move string) error {
-	return t.board.ApplyMove(player, move)
+	if player != t.player1 && player != t.player2 {
+		return fmt.Errorf("%s is not playing in this game", player)
+	}
+	if _, ok := t.rules.(turnOrder); ok && player != t.ToMove() {
+		return fmt.Errorf("It is not %s's turn", player)
+	}
+	return t.play(player, move)
 }
 
-// Outcome reports the current status of the game for each player.
-//
-// TODO(rsned): Convert this to take a player and return their outcome to
-// make it easier to simplify the game loop.
-func (t *MNKGame) Outcome() (Outcome, Outcome) {
-	return t.board.Outcome()
+// play applies the move for the player without checking whose turn it is.
+func (t *MNKGame) play(player *Player, move string) error {
+	var changes []cellChange
+	boards := t.allBoards()
+	for _, b := range boards {
+		b.journal = &changes
+	}
+	err := t.rules.ApplyMove(t, player, move)
+	for _, b := range boards {
+		b.journal = nil
+	}
+	if err != nil {
+		t.revert(changes)
+		return err
+	}
+
+	t.history = append(t.history, newMove(player, move, changes))
+	t.undone = nil
+	return nil
 }
 
-// TicTacToe returns a new instance of an m-n-k game as defined by the common Tic Tac Toe rules.
-func TicTacToe(p1, p2 *Player) *MNKGame {
-	g := &MNKGame{
-		name: "Tic-Tac-Toe",
-		rows: 3,
-		cols: 3,
-		size: 3,
+// revert puts back the cells as they were before the given changes were made.
+func (t *MNKGame) revert(changes []cellChange) {
+	boards := t.allBoards()
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		boards[c.board].cells[c.coord.Row][c.coord.Col] = c.from
+	}
+}
 
-		player1: p1,
-		player2: p2,
+// replay makes the given changes again after they have been reverted.
+func (t *MNKGame) replay(changes []cellChange) {
+	boards := t.allBoards()
+	for _, c := range changes {
+		boards[c.board].cells[c.coord.Row][c.coord.Col] = c.to
 	}
+}
 
-	g.player1.marker = MarkerX
-	g.player2.marker = MarkerWhiteStone
+// ToMove returns the player whose turn it is.
+func (t *MNKGame) ToMove() *Player {
+	if to, ok := t.rules.(turnOrder); ok {
+		return to.ToMove(t)
+	}
+	if len(t.history)%2 == 0 {
+		return t.player1
+	}
+	return t.player2
+}
 
-	g.board = newBoard(g.rows, g.cols, g.size)
+// opponent returns the other player in this game from p.
+func (t *MNKGame) opponent(p *Player) *Player {
+	if p == t.player1 {
+		return t.player2
+	}
+	return t.player1
+}
+
+// PositionKey returns a string identifying the current position, the state
+// of every cell and whose turn it is, along with any other state the rules
+// need, such as the small board to play in for Ultimate Tic-Tac-Toe. Games
+// with the same key can be treated as the same position, such as by a search
+// engine caching its results.
+func (t *MNKGame) PositionKey() string {
+	var buf strings.Builder
+	for _, b := range t.allBoards() {
+		for _, row := range b.cells {
+			for _, m := range row {
+				buf.WriteString(string(m))
+			}
+		}
+	}
+	if t.ToMove() == t.player1 {
+		buf.WriteString("0")
+	} else {
+		buf.WriteString("1")
+	}
+
+	// Captured stones can decide the game, so they are part of the position.
+	if c1, c2 := t.Captures(t.player1), t.Captures(t.player2); c1+c2 > 0 {
+		fmt.Fprintf(&buf, "/%d/%d", c1, c2)
+	}
+
+	if k, ok := t.rules.(positionKeyer); ok {
+		buf.WriteString(k.PositionKey(t))
+	}
+	return buf.String()
+}
+
+// Captures returns the number of the opponent's stones removed from the
+// board by the moves player p has played, such as pairs captured in Pente.
+func (t *MNKGame) Captures(p *Player) int {
+	n := 0
+	for _, m := range t.history {
+		if m.Player != p {
+			continue
+		}
+		for _, c := range m.changes {
+			if c.to == MarkerEmpty && c.from != t.Marker(p) {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Players returns the first and second players in this game.
+func (t *MNKGame) Players() (*Player, *Player) {
+	return t.player1, t.player2
+}
+
+// Clone returns a deep copy of the game, including its board and history,
+// that can be played forward or undone without changing this game. The
+// players and rules are shared since they describe who is playing and how,
+// not the state of the game.
+func (t *MNKGame) Clone() *MNKGame {
+	c := *t
+	c.board = t.board.Clone()
+	c.boards = nil
+	for _, b := range t.boards {
+		c.boards = append(c.boards, b.Clone())
+	}
+	c.history = slices.Clone(t.history)
+	c.undone = slices.Clone(t.undone)
+	return &c
+}
+
+// Outcome reports the current status of the game for the given player. If
+// the player is not playing in this game, OutcomeIncomplete is returned.
+func (t *MNKGame) Outcome(p *Player) Outcome {
+	p1, p2 := t.Outcomes()
+	switch p {
+	case t.player1:
+		return p1
+	case t.player2:
+		return p2
+	default:
+		return OutcomeIncomplete
+	}
+}
+
+// Outcomes reports the current status of the game for the first and second
+// players.
+func (t *MNKGame) Outcomes() (Outcome, Outcome) {
+	p1, p2 := t.rules.Outcome(t)
+	if t.misere {
+		return invert(p1), invert(p2)
+	}
+	return p1, p2
+}
+
+// SetMisere turns misère play on or off. In misère play the player who
+// would have won by completing a line loses instead, e.g. "avoid three"
+// tic-tac-toe.
+func (t *MNKGame) SetMisere(misere bool) {
+	t.misere = misere
+}
+
+// Misere reports if the game is being played misère.
+func (t *MNKGame) Misere() bool {
+	return t.misere
+}
+
+// invert swaps a win for a loss and a loss for a win.
+func invert(o Outcome) Outcome {
+	switch o {
+	case OutcomeWin:
+		return OutcomeLoss
+	case OutcomeLoss:
+		return OutcomeWin
+	default:
+		return o
+	}
+}
+
+// WinningLine returns the cells of the line that won the game, or nil if
+// the game has not been won. In misère play this is the line that lost.
+func (t *MNKGame) WinningLine() Coords {
+	if p1, p2 := t.Outcomes(); p1 != OutcomeWin && p2 != OutcomeWin {
+		return nil
+	}
+	if wl, ok := t.rules.(winningLiner); ok {
+		return wl.WinningLine(t)
+	}
+	return t.board.WinningLine()
+}
+
+// score is a rough measure of how strong the position is for player p. The
+// rules may give their own, otherwise the potential lines for p on every
+// board are counted, see lineScore.
+func (t *MNKGame) score(p *Player) int {
+	if s, ok := t.rules.(scorer); ok {
+		return s.Score(t, p)
+	}
+	score := 0
+	for _, b := range t.allBoards() {
+		score += b.lineScore(t.Marker(p), t.Marker(t.opponent(p)))
+	}
+	return score
+}
+
+// wins reports if player p playing the given move would win the game. The
+// move is taken back before returning. It need not be p's turn, so the
+// opponent's threats can be found.
+func (t *MNKGame) wins(p *Player, move string) bool {
+	if t.play(p, move) != nil {
+		return false
+	}
+	defer t.Undo()
+	return t.Outcome(p) == OutcomeWin
+}
+
+// TicTacToe returns a new instance of an m-n-k game as defined by the common Tic Tac Toe rules.
+func TicTacToe(p1, p2 *Player) *MNKGame {
+	g := NewMNKGame("Tic-Tac-Toe", 3, 3, 3, PlacementRules, p1, p2)
+	g.setMarkers(MarkerX, MarkerWhiteStone)
 
 	// For tic-tac-toe we use these common labels.
 	// TL -    Top Left, TC -    Top Center, TR -    Top Right,
@@ -101,37 +519,122 @@ func TicTacToe(p1, p2 *Player) *MNKGame {
 }
 
 // Connect4 returns a new instance using the parameters in a connect 4 game.
+//
+// Moves in Connect 4 only pick a column and the marker drops to the lowest
+// open row.
 func Connect4(p1, p2 *Player) *MNKGame {
-	g := &MNKGame{
-		name: "Connect 4",
-		rows: 6,
-		cols: 7,
-		size: 4,
+	g := NewMNKGame("Connect 4", 6, 7, 4, GravityRules, p1, p2)
+	g.board.SetLabels([]string{"", "", "", "", "", ""},
+		[]string{"1", "2", "3", "4", "5", "6", "7"})
 
-		player1: p1,
-		player2: p2,
+	return g
+}
+
+// GomokuRule selects which runs of stones win a game of Gomoku.
+type GomokuRule int
+
+// Define the enumeration of Gomoku rules.
+const (
+	// GomokuFreestyle is won by a run of five or more stones.
+	GomokuFreestyle GomokuRule = iota
+
+	// GomokuStandard is won only by a run of exactly five stones. An
+	// overline, a run of six or more, does not count.
+	GomokuStandard
+)
+
+// Gomoku returns a new instance of Gomoku, five in a row on a 15x15 board,
+// using the given rule for which runs win.
+//
+// Rows are labeled a-o and columns 1-15 so a move is written as "h8".
+func Gomoku(p1, p2 *Player, rule GomokuRule) *MNKGame {
+	name := "Gomoku"
+	if rule == GomokuStandard {
+		name = "Gomoku (Standard)"
 	}
+	g := NewMNKGame(name, 15, 15, 5, PlacementRules, p1, p2)
+	g.board.exactTarget = rule == GomokuStandard
 
-	g.board = newBoard(g.rows, g.cols, g.size)
-	g.board.SetLabels([]string{"", "", "", "", "", ""},
-		[]string{"1", "2", "3", "4", "5", "6", "7"})
+	// Black plays first in Gomoku.
+	g.setMarkers(MarkerBlackStone, MarkerWhiteStone)
+
+	g.board.SetLabels(letterLabels(15), numberLabels(15))
 
 	return g
 }
 
-/*
-TODO(rsned): Other common game options include:
+// Connect6 returns a new instance of Connect6, six in a row on a 19x19 board
+// where the first player places one stone and then each turn places two.
+//
+// Rows are labeled a-s and columns 1-19, so a move is written as "j10". The
+// two stones of a turn are two moves by the same player, or may be written
+// together as "j10+k11".
+func Connect6(p1, p2 *Player) *MNKGame {
+	g := NewMNKGame("Connect6", 19, 19, 6, Connect6Rules, p1, p2)
 
-Gomoku
-15x15 x 5
+	g.setMarkers(MarkerBlackStone, MarkerWhiteStone)
 
-Order and Chaos is a variant of the game tic-tac-toe on a 6×6 gameboard with 5 in a row
+	g.board.SetLabels(letterLabels(19), numberLabels(19))
 
-Something like Three Mens Morris or Nine Mens Morris would require a little more logic
-in the OpenPositions and ApplyMove.
+	return g
+}
 
+// Pente returns a new instance of Pente, five in a row on a 19x19 board with
+// custodial captures of pairs under PenteRules.
+//
+// Rows are labeled a-s and columns 1-19 so a move is written as "j10".
+func Pente(p1, p2 *Player) *MNKGame {
+	g := NewMNKGame("Pente", 19, 19, 5, PenteRules, p1, p2)
 
-TODO(rsned): Update game to allow custom rule handling for moves.  e.g. Connect4
-only takes moves using columns but not rows, and then 'gravity' moves the marker
-to the next available row slot in the column.
-*/
+	g.setMarkers(MarkerWhiteStone, MarkerBlackStone)
+
+	g.board.SetLabels(letterLabels(19), numberLabels(19))
+
+	return g
+}
+
+// Renju returns a new instance of Renju, Gomoku on a 15x15 board where the
+// first player, black, may not play the forbidden moves of RenjuRules.
+//
+// Rows are labeled a-o and columns 1-15 so a move is written as "h8".
+func Renju(p1, p2 *Player) *MNKGame {
+	g := NewMNKGame("Renju", 15, 15, 5, RenjuRules, p1, p2)
+
+	g.setMarkers(MarkerBlackStone, MarkerWhiteStone)
+
+	g.board.SetLabels(letterLabels(15), numberLabels(15))
+
+	return g
+}
+
+// letterLabels returns n labels a, b, c, and so on.
+func letterLabels(n int) []string {
+	labels := make([]string, n)
+	for i := range labels {
+		labels[i] = string(rune('a' + i))
+	}
+	return labels
+}
+
+// numberLabels returns n labels 1, 2, 3, and so on.
+func numberLabels(n int) []string {
+	labels := make([]string, n)
+	for i := range labels {
+		labels[i] = strconv.Itoa(i + 1)
+	}
+	return labels
+}
+
+// OrderAndChaos returns a new instance of Order and Chaos, a variant of
+// tic-tac-toe on a 6x6 board where both players may place either an X or an
+// O. The first player is Order, trying to make five in a row of either
+// symbol, and the second player is Chaos, trying to fill the board first.
+//
+// Rows are labeled a-f and columns 1-6, and a move names the cell and then
+// the symbol, e.g. "c3X".
+func OrderAndChaos(order, chaos *Player) *MNKGame {
+	g := NewMNKGame("Order and Chaos", 6, 6, 5, OrderChaosRules, order, chaos)
+	g.board.SetLabels(letterLabels(6), numberLabels(6))
+
+	return g
+}
@@ -23,3 +23,230 @@ func TestOutcome(t *testing.T) {
 		}
 	}
 }
+
+func TestPositionKey(t *testing.T) {
+	a := TicTacToe(Player1, Player2)
+	b := TicTacToe(Player1, Player2)
+	if a.PositionKey() != b.PositionKey() {
+		t.Errorf("PositionKey() of two new games differ: %q, %q", a.PositionKey(), b.PositionKey())
+	}
+
+	// The same cells reached by different move orders are the same position.
+	for _, m := range []string{"TL", "CC", "BR"} {
+		a.ApplyMove(a.ToMove(), m)
+	}
+	for _, m := range []string{"BR", "CC", "TL"} {
+		b.ApplyMove(b.ToMove(), m)
+	}
+	if a.PositionKey() != b.PositionKey() {
+		t.Errorf("PositionKey() after transposed moves differ: %q, %q", a.PositionKey(), b.PositionKey())
+	}
+
+	// Different player to move is a different position.
+	b = a.Clone()
+	b.history = b.history[:len(b.history)-1]
+	if a.PositionKey() == b.PositionKey() {
+		t.Errorf("PositionKey() with different players to move are the same: %q", a.PositionKey())
+	}
+}
+
+func TestPositionKeyRulesState(t *testing.T) {
+	tests := []struct {
+		name string
+		game func(p1, p2 *Player) *MNKGame
+		a, b []string
+	}{
+		{
+			// The same cells, but the next move is sent to the bottom left
+			// board in one and the bottom right board in the other.
+			name: "ultimate sent to different boards",
+			game: UltimateTicTacToe,
+			a:    []string{"BLTL", "TLBR", "BRTR", "TRBL"},
+			b:    []string{"BRTR", "TRBL", "BLTL", "TLBR"},
+		},
+		{
+			// Moving pieces away and back gives the same points, but is
+			// closer to a draw.
+			name: "morris quiet moves",
+			game: ThreeMensMorris,
+			a:    []string{"a1", "a2", "c2", "c1", "b3", "b1"},
+			b:    []string{"a1", "a2", "c2", "c1", "b3", "b1", "b3-a3", "a2-b2", "a3-b3", "b2-a2"},
+		},
+	}
+
+	for _, test := range tests {
+		p1, p2 := &Player{}, &Player{}
+		a, b := test.game(p1, p2), test.game(p1, p2)
+		for _, m := range test.a {
+			if err := a.ApplyMove(a.ToMove(), m); err != nil {
+				t.Fatalf("%s: ApplyMove(%q) error = %v", test.name, m, err)
+			}
+		}
+		for _, m := range test.b {
+			if err := b.ApplyMove(b.ToMove(), m); err != nil {
+				t.Fatalf("%s: ApplyMove(%q) error = %v", test.name, m, err)
+			}
+		}
+
+		// The cells and the player to move match, but the rest of the
+		// state the rules use does not.
+		if a.PositionKey() == b.PositionKey() {
+			t.Errorf("%s: PositionKey() = %q for both positions, want them to differ",
+				test.name, a.PositionKey())
+		}
+	}
+}
+
+func TestGameOutcome(t *testing.T) {
+	// Players other than the predefined Player1 and Player2 are scored by
+	// their own markers.
+	game := Connect4(PlayerComputer1, PlayerComputer2)
+	for _, m := range []string{"1", "2", "1", "2", "1", "2", "1"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+
+	tests := []struct {
+		player *Player
+		want   Outcome
+	}{
+		{player: PlayerComputer1, want: OutcomeWin},
+		{player: PlayerComputer2, want: OutcomeLoss},
+		// A player not in the game has no outcome.
+		{player: Player1, want: OutcomeIncomplete},
+	}
+
+	for _, test := range tests {
+		if got := game.Outcome(test.player); got != test.want {
+			t.Errorf("Outcome(%v) = %v, want %v", test.player, got, test.want)
+		}
+	}
+
+	if p1, p2 := game.Outcomes(); p1 != OutcomeWin || p2 != OutcomeLoss {
+		t.Errorf("Outcomes() = %v, %v, want %v, %v", p1, p2, OutcomeWin, OutcomeLoss)
+	}
+}
+
+func TestGameMarkers(t *testing.T) {
+	// Both players have the white stone, so the second is given another.
+	game := Connect4(PlayerComputer1, Player2)
+	if m1, m2 := game.Marker(PlayerComputer1), game.Marker(Player2); m1 == m2 {
+		t.Fatalf("Marker() for both players = %q, want different markers", m1)
+	}
+	for _, m := range []string{"1", "2", "1", "2", "1", "2", "3", "2"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+	if p1, p2 := game.Outcomes(); p1 != OutcomeLoss || p2 != OutcomeWin {
+		t.Errorf("Outcomes() after player 2 fills column 2 = %v, %v, want %v, %v",
+			p1, p2, OutcomeLoss, OutcomeWin)
+	}
+
+	// Players with no marker are given one.
+	p1, p2 := &Player{}, &Player{}
+	game = NewMNKGame("", 3, 3, 3, nil, p1, p2)
+	if m1, m2 := game.Marker(p1), game.Marker(p2); m1 == MarkerEmpty || m2 == MarkerEmpty || m1 == m2 {
+		t.Errorf("Marker() for players with no markers = %q, %q, want two different markers", m1, m2)
+	}
+
+	// A game's markers do not change the players or other games.
+	gomoku := Gomoku(Player1, Player2, GomokuFreestyle)
+	tictactoe := TicTacToe(Player1, Player2)
+	if got, want := gomoku.Marker(Player1), MarkerBlackStone; got != want {
+		t.Errorf("Gomoku Marker(Player1) = %q, want %q", got, want)
+	}
+	if got, want := tictactoe.Marker(Player1), MarkerX; got != want {
+		t.Errorf("Tic-Tac-Toe Marker(Player1) = %q, want %q", got, want)
+	}
+	if got, want := Player1.Marker(), MarkerX; got != want {
+		t.Errorf("Player1.Marker() after new games = %q, want %q", got, want)
+	}
+
+	// Only the players in the game may move.
+	if err := tictactoe.ApplyMove(PlayerComputer1, "TL"); err == nil {
+		t.Errorf("ApplyMove() by a player not in the game = nil, want error")
+	}
+}
+
+func TestGomoku(t *testing.T) {
+	tests := []struct {
+		rule GomokuRule
+		// black is the columns of row h holding black stones.
+		black []int
+		want  Outcome
+	}{
+		{rule: GomokuFreestyle, black: []int{2, 3, 4, 5}, want: OutcomeIncomplete},
+		{rule: GomokuFreestyle, black: []int{2, 3, 4, 5, 6}, want: OutcomeWin},
+		{rule: GomokuFreestyle, black: []int{2, 3, 4, 5, 6, 7}, want: OutcomeWin},
+		{rule: GomokuStandard, black: []int{2, 3, 4, 5, 6}, want: OutcomeWin},
+		// An overline does not count under the standard rules.
+		{rule: GomokuStandard, black: []int{2, 3, 4, 5, 6, 7}, want: OutcomeIncomplete},
+		// Even at the edge of the board.
+		{rule: GomokuStandard, black: []int{0, 1, 2, 3, 4, 5}, want: OutcomeIncomplete},
+		{rule: GomokuStandard, black: []int{0, 1, 2, 3, 4, 6}, want: OutcomeWin},
+	}
+
+	for _, test := range tests {
+		black, white := &Player{}, &Player{}
+		g := Gomoku(black, white, test.rule)
+		for _, col := range test.black {
+			g.board.cells[7][col] = g.Marker(black)
+		}
+
+		if got := g.Outcome(black); got != test.want {
+			t.Errorf("%s with black at h%v: Outcome(black) = %v, want %v",
+				g.name, test.black, got, test.want)
+		}
+		if gotLine := g.WinningLine(); (gotLine != nil) != (test.want == OutcomeWin) {
+			t.Errorf("%s with black at h%v: WinningLine() = %v", g.name, test.black, gotLine)
+		}
+	}
+
+	// Moves use the letter row and number column labels.
+	g := Gomoku(&Player{}, &Player{}, GomokuFreestyle)
+	if err := g.ApplyMove(g.ToMove(), "h8"); err != nil {
+		t.Fatalf("ApplyMove(%q) error = %v", "h8", err)
+	}
+	if got, want := g.board.cells[7][7], MarkerBlackStone; got != want {
+		t.Errorf("after h8, cell (7,7) = %q, want %q", got, want)
+	}
+	if got, want := len(g.PotentialMoves()), 15*15-1; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+}
+
+func TestMisere(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	game.SetMisere(true)
+	if !game.Misere() {
+		t.Errorf("Misere() = false after SetMisere(true)")
+	}
+
+	// Player 1 completes the top row.
+	for _, m := range []string{"TL", "CL", "TC", "CC", "TR"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+
+	if p1, p2 := game.Outcomes(); p1 != OutcomeLoss || p2 != OutcomeWin {
+		t.Errorf("Outcomes() = %v, %v, want %v, %v", p1, p2, OutcomeLoss, OutcomeWin)
+	}
+	if got := game.WinningLine(); len(got) != 3 {
+		t.Errorf("WinningLine() = %v, want the completed top row", got)
+	}
+
+	// A full board with no line is still a draw.
+	game = TicTacToe(Player1, Player2)
+	game.SetMisere(true)
+	for _, m := range []string{"TL", "TC", "TR", "CC", "CL", "CR", "BC", "BL", "BR"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+	if p1, p2 := game.Outcomes(); p1 != OutcomeDraw || p2 != OutcomeDraw {
+		t.Errorf("Outcomes() = %v, %v, want %v, %v", p1, p2, OutcomeDraw, OutcomeDraw)
+	}
+}
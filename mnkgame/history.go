@@ -0,0 +1,86 @@
+package mnkgame
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Move is the record of one move played in a game.
+type Move struct {
+	// Player is who made the move.
+	Player *Player
+
+	// Notation is the move as it was given to ApplyMove.
+	Notation string
+
+	// Coord is the cell the players marker was placed in.
+	Coord Coord
+
+	// Board is the index of the board Coord is on, 0 for the main board or
+	// i for the i'th sub-board in games played on several boards.
+	Board int
+
+	// changes are the cells changed by the move, in the order they were
+	// changed, so the move can be taken back and replayed.
+	changes []cellChange
+}
+
+// newMove creates the record of a move from the changes it made to the board.
+func newMove(p *Player, notation string, changes []cellChange) Move {
+	m := Move{
+		Player:   p,
+		Notation: notation,
+		changes:  changes,
+	}
+
+	// The first cell that gained a marker is the one the player placed.
+	for _, c := range changes {
+		if c.to != MarkerEmpty {
+			m.Coord = c.coord
+			m.Board = c.board
+			break
+		}
+	}
+	return m
+}
+
+func (m Move) String() string {
+	return fmt.Sprintf("%s: %s", m.Player, m.Notation)
+}
+
+// History returns the moves played so far in the order they were made.
+func (t *MNKGame) History() []Move {
+	return slices.Clone(t.history)
+}
+
+// MovesPlayed returns the number of moves played so far.
+func (t *MNKGame) MovesPlayed() int {
+	return len(t.history)
+}
+
+// Undo takes back the most recent move. The move can be played again with
+// Redo until a new move is applied.
+func (t *MNKGame) Undo() error {
+	if len(t.history) == 0 {
+		return fmt.Errorf("No moves to undo")
+	}
+
+	m := t.history[len(t.history)-1]
+	t.history = t.history[:len(t.history)-1]
+	t.revert(m.changes)
+	t.undone = append(t.undone, m)
+	return nil
+}
+
+// Redo plays again the most recent move taken back by Undo.
+func (t *MNKGame) Redo() error {
+	if len(t.undone) == 0 {
+		return fmt.Errorf("No moves to redo")
+	}
+
+	m := t.undone[len(t.undone)-1]
+	t.undone = t.undone[:len(t.undone)-1]
+	t.replay(m.changes)
+	t.history = append(t.history, m)
+	return nil
+}
@@ -0,0 +1,90 @@
+package mnkgame
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHistory(t *testing.T) {
+	game := Connect4(Player1, Player2)
+	for _, m := range []string{"4", "4", "5"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+	// Illegal moves are not recorded.
+	if err := game.ApplyMove(game.ToMove(), "9"); err == nil {
+		t.Fatalf("ApplyMove(%q) = nil, want error", "9")
+	}
+
+	want := []Move{
+		{Player: Player1, Notation: "4", Coord: Coord{Row: 5, Col: 3}},
+		{Player: Player2, Notation: "4", Coord: Coord{Row: 4, Col: 3}},
+		{Player: Player1, Notation: "5", Coord: Coord{Row: 5, Col: 4}},
+	}
+	got := game.History()
+	if len(got) != len(want) {
+		t.Fatalf("History() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Player != want[i].Player || got[i].Notation != want[i].Notation ||
+			!got[i].Coord.equals(want[i].Coord) {
+			t.Errorf("History()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	start := game.PotentialMoves()
+
+	if err := game.Undo(); err == nil {
+		t.Errorf("Undo() on a new game = nil, want error")
+	}
+	if err := game.Redo(); err == nil {
+		t.Errorf("Redo() on a new game = nil, want error")
+	}
+
+	for _, m := range []string{"TL", "CC"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+	afterTwo := game.PotentialMoves()
+
+	if err := game.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if err := game.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := game.PotentialMoves(); !cmp.Equal(got, start) {
+		t.Errorf("PotentialMoves() after undoing all moves = %v, want %v", got, start)
+	}
+	if got := game.ToMove(); got != Player1 {
+		t.Errorf("ToMove() after undoing all moves = %v, want %v", got, Player1)
+	}
+
+	if err := game.Redo(); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	if err := game.Redo(); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	if got := game.PotentialMoves(); !cmp.Equal(got, afterTwo) {
+		t.Errorf("PotentialMoves() after redoing = %v, want %v", got, afterTwo)
+	}
+
+	// A new move clears the redo list.
+	game.Undo()
+	if err := game.ApplyMove(game.ToMove(), "BR"); err != nil {
+		t.Fatalf("ApplyMove(%q) error = %v", "BR", err)
+	}
+	if err := game.Redo(); err == nil {
+		t.Errorf("Redo() after a new move = nil, want error")
+	}
+	if got, want := game.MovesPlayed(), 2; got != want {
+		t.Errorf("MovesPlayed() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,154 @@
+package mnkgame
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// layeredRules is k-in-a-row on a three dimensional board. Each layer is a
+// sub-board of the game, and lines may run in any of the 13 directions
+// through the layers. The main board has a cell for each layer that is
+// filled once the layer is full.
+//
+// Moves are the 1-based layer number followed by the cell on that layer,
+// e.g. "2b3".
+type layeredRules struct {
+	// lines are all the winning lines through the layers.
+	lines CoordsList
+}
+
+// cell returns the marker at c, where c.Layer picks the sub-board.
+func (layeredRules) cell(g *MNKGame, c Coord) Marker {
+	return g.boards[c.Layer].cells[c.Row][c.Col]
+}
+
+func (layeredRules) LegalMoves(g *MNKGame) []string {
+	var moves []string
+	for i, b := range g.boards {
+		for _, pos := range b.OpenPositions() {
+			moves = append(moves, strconv.Itoa(i+1)+pos)
+		}
+	}
+	return moves
+}
+
+func (layeredRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	i, cell, ok := splitBoardNumber(move, len(g.boards))
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+
+	b := g.boards[i]
+	c, ok := b.decodeMove(cell)
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	if b.cells[c.Row][c.Col] != MarkerEmpty {
+		return fmt.Errorf("Move %q not available", move)
+	}
+
+	b.set(c, g.Marker(p))
+	if b.full() {
+		g.board.set(Coord{Row: 0, Col: i}, MarkerBlocked)
+	}
+	return nil
+}
+
+func (r layeredRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	switch r.winningMarker(g) {
+	case MarkerEmpty:
+	case g.marker1:
+		return OutcomeWin, OutcomeLoss
+	case g.marker2:
+		return OutcomeLoss, OutcomeWin
+	}
+
+	if g.board.full() {
+		return OutcomeDraw, OutcomeDraw
+	}
+	return OutcomeIncomplete, OutcomeIncomplete
+}
+
+// winningMarker returns the marker of the first completed line, or
+// MarkerEmpty if there is none.
+func (r layeredRules) winningMarker(g *MNKGame) Marker {
+	if line := r.WinningLine(g); line != nil {
+		return r.cell(g, line[0])
+	}
+	return MarkerEmpty
+}
+
+// WinningLine returns the cells of the first completed line through the
+// layers, or nil if there is none.
+func (r layeredRules) WinningLine(g *MNKGame) Coords {
+	for _, line := range r.lines {
+		m := r.cell(g, line[0])
+		if m == MarkerEmpty {
+			continue
+		}
+		win := true
+		for _, c := range line[1:] {
+			win = win && r.cell(g, c) == m
+		}
+		if win {
+			return line
+		}
+	}
+	return nil
+}
+
+// Score is lineScore for the lines through the layers. Each line the
+// opponent has not blocked adds the square of the number of p's markers in
+// it.
+func (r layeredRules) Score(g *MNKGame, p *Player) int {
+	mine, opponent := g.Marker(p), g.Marker(g.opponent(p))
+	score := 0
+	for _, line := range r.lines {
+		n := 0
+		blocked := false
+		for _, c := range line {
+			switch r.cell(g, c) {
+			case mine:
+				n++
+			case opponent:
+				blocked = true
+			}
+		}
+		if !blocked {
+			score += n * n
+		}
+	}
+	return score
+}
+
+// NewLayeredGame returns a new game with the given name on a three
+// dimensional board of layers x rows x cols where size markers in a row, in
+// any direction through the layers, are needed to win.
+//
+// Each layer has rows labeled a, b, c... and columns 1, 2, 3..., and a move
+// starts with the 1-based layer number, so "2b3" is the second row and third
+// column of the second layer.
+func NewLayeredGame(name string, layers, rows, cols, size int, p1, p2 *Player) *MNKGame {
+	rules := layeredRules{lines: generateWinningLines(layers, rows, cols, size)}
+	g := NewMNKGame(name, 1, layers, layers, rules, p1, p2)
+
+	// The cells of the main board only record the full layers, they do not
+	// make lines.
+	g.board.winTests = nil
+
+	g.addBoards(layers, rows, cols, size)
+	for _, b := range g.boards {
+		b.SetLabels(letterLabels(rows), numberLabels(cols))
+	}
+
+	return g
+}
+
+// Qubic returns a new instance of Qubic, four in a row on a 4x4x4 cube.
+func Qubic(p1, p2 *Player) *MNKGame {
+	g := NewLayeredGame("Qubic", 4, 4, 4, 4, p1, p2)
+
+	g.setMarkers(MarkerX, MarkerWhiteStone)
+
+	return g
+}
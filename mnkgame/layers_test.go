@@ -0,0 +1,234 @@
+package mnkgame
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGenerateWinningLines(t *testing.T) {
+	tests := []struct {
+		layers, rows, cols, size int
+		want                     int
+	}{
+		// A flat board has only the four flat directions.
+		{layers: 1, rows: 3, cols: 3, size: 3, want: 8},
+		{layers: 1, rows: 1, cols: 1, size: 1, want: 1},
+		// 3D tic-tac-toe.
+		{layers: 3, rows: 3, cols: 3, size: 3, want: 49},
+		// Qubic.
+		{layers: 4, rows: 4, cols: 4, size: 4, want: 76},
+		// Five layers deep but only three wide, so lines only run down
+		// through the layers, from either of the top two.
+		{layers: 5, rows: 3, cols: 3, size: 4, want: 18},
+	}
+
+	for _, test := range tests {
+		got := generateWinningLines(test.layers, test.rows, test.cols, test.size)
+		if len(got) != test.want {
+			t.Errorf("generateWinningLines(%d, %d, %d, %d) has %d lines, want %d",
+				test.layers, test.rows, test.cols, test.size, len(got), test.want)
+		}
+		for _, line := range got {
+			if len(line) != test.size {
+				t.Errorf("generateWinningLines(%d, %d, %d, %d) line %v has %d cells, want %d",
+					test.layers, test.rows, test.cols, test.size, line, len(line), test.size)
+			}
+		}
+	}
+}
+
+func TestQubic(t *testing.T) {
+	tests := []struct {
+		name string
+		// moves alternate between player 1 and player 2.
+		moves    []string
+		wantErr  bool
+		wantP1   Outcome
+		wantLine Coords
+	}{
+		{
+			name:    "bad layer",
+			moves:   []string{"5a1"},
+			wantErr: true,
+		},
+		{
+			name:    "no layer",
+			moves:   []string{"a1"},
+			wantErr: true,
+		},
+		{
+			name:    "bad cell",
+			moves:   []string{"1e1"},
+			wantErr: true,
+		},
+		{
+			name:    "taken",
+			moves:   []string{"1a1", "1a1"},
+			wantErr: true,
+		},
+		{
+			name:   "in progress",
+			moves:  []string{"1a1", "1a2", "2a1", "2a2", "3a1", "3a2"},
+			wantP1: OutcomeIncomplete,
+		},
+		{
+			name:   "straight down through the layers",
+			moves:  []string{"1a1", "1a2", "2a1", "2a2", "3a1", "3a2", "4a1"},
+			wantP1: OutcomeWin,
+			wantLine: Coords{
+				{Layer: 0, Row: 0, Col: 0},
+				{Layer: 1, Row: 0, Col: 0},
+				{Layer: 2, Row: 0, Col: 0},
+				{Layer: 3, Row: 0, Col: 0},
+			},
+		},
+		{
+			name:   "corner to corner",
+			moves:  []string{"1d1", "1a1", "2c2", "1a2", "3b3", "1a3", "4a4"},
+			wantP1: OutcomeWin,
+			wantLine: Coords{
+				{Layer: 0, Row: 3, Col: 0},
+				{Layer: 1, Row: 2, Col: 1},
+				{Layer: 2, Row: 1, Col: 2},
+				{Layer: 3, Row: 0, Col: 3},
+			},
+		},
+		{
+			name:   "player 2 in one layer",
+			moves:  []string{"1a1", "2b1", "3a1", "2b2", "4d4", "2b3", "1c4", "2b4"},
+			wantP1: OutcomeLoss,
+			wantLine: Coords{
+				{Layer: 1, Row: 1, Col: 0},
+				{Layer: 1, Row: 1, Col: 1},
+				{Layer: 1, Row: 1, Col: 2},
+				{Layer: 1, Row: 1, Col: 3},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		p1, p2 := &Player{}, &Player{}
+		g := Qubic(p1, p2)
+		if got, want := len(g.PotentialMoves()), 64; got != want {
+			t.Errorf("%s: len(PotentialMoves()) = %d, want %d", test.name, got, want)
+		}
+		// The main board only records full layers, so it has no lines.
+		if got := len(g.board.winTests); got != 0 {
+			t.Errorf("%s: main board has %d winning lines, want 0", test.name, got)
+		}
+
+		var err error
+		var move string
+		for _, move = range test.moves {
+			if err = g.ApplyMove(g.ToMove(), move); err != nil {
+				break
+			}
+		}
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: ApplyMove() error = %v, want error %v", test.name, err, test.wantErr)
+		}
+		if test.wantErr {
+			// The error names the whole move, not only the cell.
+			if err != nil && !strings.Contains(err.Error(), strconv.Quote(move)) {
+				t.Errorf("%s: ApplyMove(%q) error = %v, want it to name the move", test.name, move, err)
+			}
+			continue
+		}
+
+		if got := g.Outcome(p1); got != test.wantP1 {
+			t.Errorf("%s: Outcome(p1) = %v, want %v", test.name, got, test.wantP1)
+		}
+		if got := g.WinningLine(); !cmpCoords(got, test.wantLine) {
+			t.Errorf("%s: WinningLine() = %v, want %v", test.name, got, test.wantLine)
+		}
+
+		// Each layer's view only has its own part of the line.
+		for i, v := range g.Boards() {
+			var want Coords
+			for _, c := range test.wantLine {
+				if c.Layer == i {
+					want = append(want, Coord{Row: c.Row, Col: c.Col})
+				}
+			}
+			if got := v.WinningLine(); !cmpCoords(got, want) {
+				t.Errorf("%s: layer %d WinningLine() = %v, want %v", test.name, i+1, got, want)
+			}
+		}
+	}
+}
+
+func TestCoordsString(t *testing.T) {
+	tests := []struct {
+		have Coords
+		want string
+	}{
+		{have: nil, want: "[]"},
+		{have: Coords{{Row: 0, Col: 0}, {Row: 1, Col: 2}}, want: "[(0,0) (1,2)]"},
+		// Once one coord is on another layer, all of them show their layer.
+		{
+			have: Coords{{Layer: 0, Row: 0, Col: 0}, {Layer: 1, Row: 0, Col: 1}},
+			want: "[(0,0,0) (1,0,1)]",
+		},
+	}
+
+	for _, test := range tests {
+		if got := test.have.String(); got != test.want {
+			t.Errorf("String() = %q, want %q", got, test.want)
+		}
+		if got := fmt.Sprint(test.have); got != test.want {
+			t.Errorf("fmt.Sprint() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+// cmpCoords reports if the two lists of coords are the same, treating nil
+// and empty as equal.
+func cmpCoords(a, b Coords) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLayeredRenderer(t *testing.T) {
+	g := Qubic(&Player{}, &Player{})
+	g.ApplyMove(g.ToMove(), "2b3")
+
+	// A compact 4x4 layer with labels on both sides.
+	single := 6
+
+	tests := []struct {
+		sideBySide bool
+		wantLines  int
+	}{
+		// A heading and the board for each layer, with blank lines between.
+		{sideBySide: false, wantLines: 4*(single+1) + 3},
+		{sideBySide: true, wantLines: single + 1},
+	}
+	for _, test := range tests {
+		r := NewLayeredRenderer(NewTextRenderer(CompactBoardOptions()))
+		r.SideBySide = test.sideBySide
+
+		var buf bytes.Buffer
+		if err := g.Render(&buf, r); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		got := buf.String()
+		if n := strings.Count(got, "\n"); n != test.wantLines {
+			t.Errorf("Render() with SideBySide %v has %d lines, want %d:\n%s", test.sideBySide, n, test.wantLines, got)
+		}
+		for _, h := range []string{"Layer 1", "Layer 2", "Layer 3", "Layer 4"} {
+			if !strings.Contains(got, h) {
+				t.Errorf("Render() with SideBySide %v is missing %q", test.sideBySide, h)
+			}
+		}
+	}
+}
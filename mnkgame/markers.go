@@ -7,6 +7,7 @@ const (
 	filledBlackCircle = "⚫" // U+26AB MEDIUM BLACK CIRCLE
 	filledWhiteCircle = "⭘" // U+2B58 HEAVY CIRCLE
 	blackX            = "🗙" // U+1F5D9 CANCELLATION X
+	lightShade        = "░" // U+2591 LIGHT SHADE
 
 	winMarkerUpArrowWhite    = "▵" // U+25B5 - WHITE UP-POINTING SMALL TRIANGLE
 	winMarkerDownArrowWhite  = "▿" // U+25BF - WHITE DOWN-POINTING SMALL TRIANGLE
@@ -27,4 +28,8 @@ var (
 	MarkerX          = Marker(blackX)
 	MarkerWhiteStone = Marker(filledWhiteCircle)
 	MarkerBlackStone = Marker(filledBlackCircle)
+
+	// MarkerBlocked fills cells that are not part of the game and can never
+	// be played, such as the gaps between the points in Nine Mens Morris.
+	MarkerBlocked = Marker(lightShade)
 )
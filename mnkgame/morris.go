@@ -0,0 +1,401 @@
+package mnkgame
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Predefine the Morris rule sets.
+var (
+	// ThreeMensMorrisRules has each player place three pieces on a 3x3
+	// board and then move them one step along a line. The first player to
+	// get three in a line, a mill, wins.
+	ThreeMensMorrisRules Rules = threeMensMorris
+
+	// NineMensMorrisRules has each player place nine pieces on the 24
+	// points of the Nine Men's Morris board and then move them one step
+	// along a line. Making a mill removes one of the opponent's pieces, and
+	// a player loses when left with two pieces or no moves. A player with
+	// only three pieces left may move to any empty point.
+	NineMensMorrisRules Rules = nineMensMorris
+)
+
+var (
+	threeMensMorris = newMorrisRules(3, threeMensMorrisLines(), false)
+	nineMensMorris  = newMorrisRules(9, nineMensMorrisLines(), true)
+)
+
+// morrisDrawMoves is how many moves in a row may be made in the movement
+// phase without removing a piece before the game is a draw.
+const morrisDrawMoves = 50
+
+// morrisRules is the family of games where pieces are first placed and then
+// moved along the lines of the board.
+//
+// Moves during the placement phase are a point, e.g. "a1", and during the
+// movement phase a from-to pair, e.g. "a1-d1". When a move makes a mill and
+// pieces are removed, the piece to remove follows an x, e.g. "a1-d1xg7".
+type morrisRules struct {
+	// pieces is the number each player places before moving.
+	pieces int
+
+	// lines are the sets of points that form a mill. Pieces move between
+	// neighboring points on a line.
+	lines CoordsList
+
+	// adjacent is the neighboring points of each point on the board.
+	adjacent map[Coord][]Coord
+
+	// removal is set when a mill removes an opponent's piece rather than
+	// winning the game.
+	removal bool
+}
+
+// newMorrisRules returns the rules for the given number of pieces per player
+// on a board with the given lines.
+func newMorrisRules(pieces int, lines CoordsList, removal bool) morrisRules {
+	r := morrisRules{
+		pieces:   pieces,
+		lines:    lines,
+		adjacent: map[Coord][]Coord{},
+		removal:  removal,
+	}
+	for _, line := range lines {
+		for i := 1; i < len(line); i++ {
+			a, b := line[i-1], line[i]
+			r.adjacent[a] = append(r.adjacent[a], b)
+			r.adjacent[b] = append(r.adjacent[b], a)
+		}
+	}
+	return r
+}
+
+// threeMensMorrisLines returns the rows, columns, and diagonals of a 3x3 board.
+func threeMensMorrisLines() CoordsList {
+	return generateWinningLines(1, 3, 3, 3)
+}
+
+// nineMensMorrisLines returns the sides of the three nested squares and the
+// four lines joining their midpoints on a 7x7 grid.
+func nineMensMorrisLines() CoordsList {
+	var lines CoordsList
+	for ring := 0; ring < 3; ring++ {
+		lo, hi := ring, 6-ring
+		lines = append(lines,
+			Coords{{Row: lo, Col: lo}, {Row: lo, Col: 3}, {Row: lo, Col: hi}},
+			Coords{{Row: hi, Col: lo}, {Row: hi, Col: 3}, {Row: hi, Col: hi}},
+			Coords{{Row: lo, Col: lo}, {Row: 3, Col: lo}, {Row: hi, Col: lo}},
+			Coords{{Row: lo, Col: hi}, {Row: 3, Col: hi}, {Row: hi, Col: hi}},
+		)
+	}
+	lines = append(lines,
+		Coords{{Row: 0, Col: 3}, {Row: 1, Col: 3}, {Row: 2, Col: 3}},
+		Coords{{Row: 4, Col: 3}, {Row: 5, Col: 3}, {Row: 6, Col: 3}},
+		Coords{{Row: 3, Col: 0}, {Row: 3, Col: 1}, {Row: 3, Col: 2}},
+		Coords{{Row: 3, Col: 4}, {Row: 3, Col: 5}, {Row: 3, Col: 6}},
+	)
+	slices.SortFunc(lines, coordSliceCompare)
+	return lines
+}
+
+// newMorrisGame returns a game on a rows x cols board using the given Morris
+// rules. Cells that are not on any line are blocked.
+func newMorrisGame(name string, rows, cols int, r morrisRules, p1, p2 *Player) *MNKGame {
+	g := NewMNKGame(name, rows, cols, 3, r, p1, p2)
+
+	points := map[Coord]bool{}
+	for _, line := range r.lines {
+		for _, c := range line {
+			points[c] = true
+		}
+	}
+	for i := range g.board.cells {
+		for j := range g.board.cells[i] {
+			if !points[Coord{Row: i, Col: j}] {
+				g.board.cells[i][j] = MarkerBlocked
+			}
+		}
+	}
+
+	// The mills are the only lines that count on this board.
+	g.board.winTests = r.lines
+
+	g.setMarkers(MarkerWhiteStone, MarkerBlackStone)
+
+	g.board.SetLabels(letterLabels(rows), numberLabels(cols))
+
+	return g
+}
+
+// placing reports if the game is still in the placement phase.
+func (r morrisRules) placing(g *MNKGame) bool {
+	return len(g.history) < 2*r.pieces
+}
+
+// inMill reports if the point c is part of a full line of marker m.
+func (r morrisRules) inMill(b *Board, c Coord, m Marker) bool {
+	for _, line := range r.lines {
+		if slices.Contains(line, c) && b.checkLine(line, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// makesMill reports if moving a piece of marker m from the point from, or
+// placing one when from is nil, to the point to would complete a mill. The
+// board is not changed.
+func (r morrisRules) makesMill(b *Board, from *Coord, to Coord, m Marker) bool {
+	for _, line := range r.lines {
+		if !slices.Contains(line, to) {
+			continue
+		}
+		mill := true
+		for _, c := range line {
+			if c == to {
+				continue
+			}
+			if (from != nil && c == *from) || b.cells[c.Row][c.Col] != m {
+				mill = false
+				break
+			}
+		}
+		if mill {
+			return true
+		}
+	}
+	return false
+}
+
+// piecesOf returns the points holding marker m.
+func (r morrisRules) piecesOf(b *Board, m Marker) []Coord {
+	var pieces []Coord
+	for i, row := range b.cells {
+		for j, cell := range row {
+			if cell == m {
+				pieces = append(pieces, Coord{Row: i, Col: j})
+			}
+		}
+	}
+	return pieces
+}
+
+// removable returns the pieces of marker m that may be removed. Pieces in a
+// mill are only removable if every piece is in a mill.
+func (r morrisRules) removable(b *Board, m Marker) []Coord {
+	pieces := r.piecesOf(b, m)
+	free := slices.DeleteFunc(slices.Clone(pieces), func(c Coord) bool {
+		return r.inMill(b, c, m)
+	})
+	if len(free) == 0 {
+		return pieces
+	}
+	return free
+}
+
+// destinations returns the points the piece at from may move to.
+func (r morrisRules) destinations(b *Board, from Coord, m Marker) []Coord {
+	if r.removal && len(r.piecesOf(b, m)) == 3 {
+		// Down to three pieces, so they fly to any empty point.
+		return r.piecesOf(b, MarkerEmpty)
+	}
+	var dests []Coord
+	for _, c := range r.adjacent[from] {
+		if b.cells[c.Row][c.Col] == MarkerEmpty {
+			dests = append(dests, c)
+		}
+	}
+	return dests
+}
+
+// label returns the notation for the point c.
+func (r morrisRules) label(b *Board, c Coord) string {
+	return b.rowLabels[c.Row] + b.colLabels[c.Col]
+}
+
+func (r morrisRules) LegalMoves(g *MNKGame) []string {
+	b := g.board
+	p := g.ToMove()
+	mine, opponent := g.Marker(p), g.Marker(g.opponent(p))
+
+	var moves []string
+	add := func(notation string, from *Coord, to Coord) {
+		// The move only changes the mover's pieces, so the opponent's
+		// removable pieces are the same before and after it.
+		var captures []Coord
+		if r.removal && r.makesMill(b, from, to, mine) {
+			captures = r.removable(b, opponent)
+		}
+
+		if len(captures) == 0 {
+			moves = append(moves, notation)
+			return
+		}
+		for _, c := range captures {
+			moves = append(moves, notation+"x"+r.label(b, c))
+		}
+	}
+
+	if r.placing(g) {
+		for _, to := range r.piecesOf(b, MarkerEmpty) {
+			add(r.label(b, to), nil, to)
+		}
+		return moves
+	}
+
+	for _, from := range r.piecesOf(b, mine) {
+		for _, to := range r.destinations(b, from, mine) {
+			add(r.label(b, from)+"-"+r.label(b, to), &from, to)
+		}
+	}
+	return moves
+}
+
+func (r morrisRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	b := g.board
+	mine, opponent := g.Marker(p), g.Marker(g.opponent(p))
+	base, capture, hasCapture := strings.Cut(move, "x")
+	fromLabel, toLabel, moving := strings.Cut(base, "-")
+	if !moving {
+		toLabel = base
+	}
+
+	placing := r.placing(g)
+	if placing && moving {
+		return fmt.Errorf("Pieces can not move until all of them are placed")
+	}
+	if !placing && !moving {
+		return fmt.Errorf("All pieces are placed, moves must be from-to, e.g. a1-a4")
+	}
+
+	to, ok := b.decodeMove(toLabel)
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	if b.cells[to.Row][to.Col] != MarkerEmpty {
+		return fmt.Errorf("Move not available")
+	}
+
+	if moving {
+		from, ok := b.decodeMove(fromLabel)
+		if !ok {
+			return fmt.Errorf("Unable to decipher the requested move: %q", move)
+		}
+		if b.cells[from.Row][from.Col] != mine {
+			return fmt.Errorf("No piece of yours at %q", fromLabel)
+		}
+		if !slices.Contains(r.destinations(b, from, mine), to) {
+			return fmt.Errorf("Piece at %q can not move to %q", fromLabel, toLabel)
+		}
+		b.set(from, MarkerEmpty)
+	}
+	b.set(to, mine)
+
+	mill := r.removal && r.inMill(b, to, mine)
+	switch {
+	case hasCapture && !mill:
+		return fmt.Errorf("Move does not make a mill, no piece can be removed")
+	case hasCapture:
+		c, ok := b.decodeMove(capture)
+		if !ok || !slices.Contains(r.removable(b, opponent), c) {
+			return fmt.Errorf("Piece at %q can not be removed", capture)
+		}
+		b.set(c, MarkerEmpty)
+	case mill && len(r.piecesOf(b, opponent)) > 0:
+		return fmt.Errorf("Move makes a mill, name a piece to remove, e.g. %sx%s",
+			base, r.label(b, r.removable(b, opponent)[0]))
+	}
+	return nil
+}
+
+func (r morrisRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	b := g.board
+	p1, p2 := g.marker1, g.marker2
+
+	if !r.removal {
+		for _, line := range r.lines {
+			if b.checkLine(line, p1) {
+				return OutcomeWin, OutcomeLoss
+			}
+			if b.checkLine(line, p2) {
+				return OutcomeLoss, OutcomeWin
+			}
+		}
+	}
+
+	if r.placing(g) {
+		return OutcomeIncomplete, OutcomeIncomplete
+	}
+
+	if r.removal {
+		if len(r.piecesOf(b, p1)) < 3 {
+			return OutcomeLoss, OutcomeWin
+		}
+		if len(r.piecesOf(b, p2)) < 3 {
+			return OutcomeWin, OutcomeLoss
+		}
+	}
+
+	// A player with no moves loses.
+	if len(r.LegalMoves(g)) == 0 {
+		if g.ToMove() == g.player1 {
+			return OutcomeLoss, OutcomeWin
+		}
+		return OutcomeWin, OutcomeLoss
+	}
+
+	if r.quietMoves(g) >= morrisDrawMoves {
+		return OutcomeDraw, OutcomeDraw
+	}
+	return OutcomeIncomplete, OutcomeIncomplete
+}
+
+// WinningLine returns the mill that won a game where a mill wins, such as
+// Three Mens Morris. When mills remove pieces instead, the game is won by
+// taking pieces or leaving the opponent no moves, so there is no line.
+func (r morrisRules) WinningLine(g *MNKGame) Coords {
+	if r.removal {
+		return nil
+	}
+	return g.board.WinningLine()
+}
+
+// PositionKey adds the number of pieces left to place, or once they are all
+// placed, the moves made without removing a piece. The same points can be
+// reached while placing and while moving, or closer to a draw.
+func (r morrisRules) PositionKey(g *MNKGame) string {
+	if r.placing(g) {
+		return fmt.Sprintf("+%d", 2*r.pieces-len(g.history))
+	}
+	return fmt.Sprintf("~%d", r.quietMoves(g))
+}
+
+// quietMoves returns the number of moves at the end of the movement phase
+// made without removing a piece.
+func (r morrisRules) quietMoves(g *MNKGame) int {
+	n := 0
+	for i := len(g.history) - 1; i >= 2*r.pieces; i-- {
+		m := g.history[i]
+		for _, c := range m.changes {
+			if c.to == MarkerEmpty && c.from != g.Marker(m.Player) {
+				return n
+			}
+		}
+		n++
+	}
+	return n
+}
+
+// ThreeMensMorris returns a new instance of Three Men's Morris on a 3x3
+// board with rows labeled a-c and columns 1-3.
+func ThreeMensMorris(p1, p2 *Player) *MNKGame {
+	return newMorrisGame("Three Men's Morris", 3, 3, threeMensMorris, p1, p2)
+}
+
+// NineMensMorris returns a new instance of Nine Men's Morris. The 24 points
+// are laid out on a 7x7 board with rows labeled a-g and columns 1-7, and the
+// cells between them are blocked.
+func NineMensMorris(p1, p2 *Player) *MNKGame {
+	return newMorrisGame("Nine Men's Morris", 7, 7, nineMensMorris, p1, p2)
+}
@@ -0,0 +1,249 @@
+package mnkgame
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestThreeMensMorris(t *testing.T) {
+	p1, p2 := &Player{}, &Player{}
+	g := ThreeMensMorris(p1, p2)
+
+	tests := []struct {
+		move    string
+		wantErr bool
+	}{
+		{move: "a1"},
+		{move: "b1"},
+		// Pieces can't move while placing.
+		{move: "a1-a2", wantErr: true},
+		{move: "a2"},
+		{move: "b2"},
+		{move: "c3"},
+		{move: "c1"},
+		// All placed, so only from-to moves.
+		{move: "a3", wantErr: true},
+		// Not a neighbor.
+		{move: "c3-a3", wantErr: true},
+		// Not your piece.
+		{move: "b2-b3", wantErr: true},
+		{move: "c3-b3"},
+		{move: "b2-c2"},
+		{move: "b3-a3"},
+	}
+	for _, test := range tests {
+		err := g.ApplyMove(g.ToMove(), test.move)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ApplyMove(%q) error = %v, want error %v", test.move, err, test.wantErr)
+		}
+		if err == nil && test.move != "b3-a3" {
+			if got := g.Outcome(p1); got != OutcomeIncomplete {
+				t.Errorf("after %q, Outcome(p1) = %v, want %v", test.move, got, OutcomeIncomplete)
+			}
+		}
+	}
+
+	if got := g.Outcome(p1); got != OutcomeWin {
+		t.Errorf("Outcome(p1) = %v, want %v", got, OutcomeWin)
+	}
+	if got, want := g.WinningLine(), (Coords{{Row: 0, Col: 0}, {Row: 0, Col: 1}, {Row: 0, Col: 2}}); !slices.Equal(got, want) {
+		t.Errorf("WinningLine() = %v, want %v", got, want)
+	}
+}
+
+func TestNineMensMorrisPlacement(t *testing.T) {
+	g := NineMensMorris(&Player{}, &Player{})
+
+	if got, want := len(g.PotentialMoves()), 24; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+
+	tests := []struct {
+		move    string
+		wantErr bool
+	}{
+		// Not a point on the board.
+		{move: "a2", wantErr: true},
+		{move: "a1"},
+		{move: "g1"},
+		{move: "a4"},
+		{move: "g4"},
+		// A mill must name a piece to remove.
+		{move: "a7", wantErr: true},
+		{move: "a7xa4", wantErr: true},
+		{move: "a7xg1"},
+		// Removing without a mill.
+		{move: "b2xa1", wantErr: true},
+		{move: "b2"},
+	}
+	for _, test := range tests {
+		if test.move == "a7xg1" {
+			moves := g.PotentialMoves()
+			for _, want := range []string{"a7xg1", "a7xg4"} {
+				if !slices.Contains(moves, want) {
+					t.Errorf("PotentialMoves() = %v, want it to contain %q", moves, want)
+				}
+			}
+		}
+
+		err := g.ApplyMove(g.ToMove(), test.move)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ApplyMove(%q) error = %v, want error %v", test.move, err, test.wantErr)
+		}
+	}
+
+	if got := g.board.cells[6][0]; got != MarkerEmpty {
+		t.Errorf("removed piece at g1 = %q, want %q", got, MarkerEmpty)
+	}
+}
+
+func TestNineMensMorrisMovement(t *testing.T) {
+	tests := []struct {
+		name      string
+		p1, p2    []string
+		quiet     int
+		move      string
+		wantErr   bool
+		wantP1    Outcome
+		wantMoves int
+	}{
+		{
+			name:      "step along a line",
+			p1:        []string{"a1", "a4", "d1", "b2"},
+			p2:        []string{"g1", "g4", "g7", "f6"},
+			move:      "a1-a7",
+			wantErr:   true,
+			wantP1:    OutcomeIncomplete,
+			wantMoves: 5,
+		},
+		{
+			// With three pieces left, they may fly anywhere.
+			name:      "flying",
+			p1:        []string{"a1", "a4", "d1"},
+			p2:        []string{"g1", "g4", "g7", "f6"},
+			move:      "a1-g2",
+			wantErr:   true,
+			wantP1:    OutcomeIncomplete,
+			wantMoves: 3 * 17,
+		},
+		{
+			name:   "two pieces left",
+			p1:     []string{"a1", "a4", "d1"},
+			p2:     []string{"g1", "g4", "f6"},
+			move:   "d1-a7xg1",
+			wantP1: OutcomeWin,
+		},
+		{
+			name:   "no moves",
+			p1:     []string{"a1"},
+			p2:     []string{"a4", "d1", "g7"},
+			wantP1: OutcomeLoss,
+		},
+		{
+			name:      "too many quiet moves",
+			p1:        []string{"a1", "a4", "d1", "b2"},
+			p2:        []string{"g1", "g4", "g7", "f6"},
+			quiet:     morrisDrawMoves,
+			wantP1:    OutcomeDraw,
+			wantMoves: 5,
+		},
+	}
+
+	for _, test := range tests {
+		p1, p2 := &Player{}, &Player{}
+		g := NineMensMorris(p1, p2)
+		for _, m := range test.p1 {
+			c, _ := g.board.decodeMove(m)
+			g.board.cells[c.Row][c.Col] = g.Marker(p1)
+		}
+		for _, m := range test.p2 {
+			c, _ := g.board.decodeMove(m)
+			g.board.cells[c.Row][c.Col] = g.Marker(p2)
+		}
+		// Fill in the history so the placement phase is over and it is
+		// player 1 to move.
+		for i := 0; i < 18+test.quiet; i++ {
+			g.history = append(g.history, Move{Player: []*Player{p1, p2}[i%2]})
+		}
+
+		if test.wantMoves > 0 {
+			if got := len(g.PotentialMoves()); got != test.wantMoves {
+				t.Errorf("%s: len(PotentialMoves()) = %d, want %d", test.name, got, test.wantMoves)
+			}
+		}
+		if test.move != "" {
+			err := g.ApplyMove(p1, test.move)
+			if (err != nil) != test.wantErr {
+				t.Errorf("%s: ApplyMove(%q) error = %v, want error %v", test.name, test.move, err, test.wantErr)
+			}
+		}
+		if got := g.Outcome(p1); got != test.wantP1 {
+			t.Errorf("%s: Outcome(p1) = %v, want %v", test.name, got, test.wantP1)
+		}
+		// The game is not won by a line, even with a mill on the board.
+		if got := g.WinningLine(); got != nil {
+			t.Errorf("%s: WinningLine() = %v, want nil", test.name, got)
+		}
+	}
+}
+
+func TestNineMensMorrisWinningLine(t *testing.T) {
+	p1, p2 := &Player{}, &Player{}
+	g := NineMensMorris(p1, p2)
+	for _, m := range []string{"a1", "g1", "a4", "g4", "a7xg1"} {
+		if err := g.ApplyMove(g.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+
+	// A mill only removes a piece, so it is not drawn as a winning line.
+	if got := g.Outcome(p1); got != OutcomeIncomplete {
+		t.Errorf("Outcome(p1) = %v, want %v", got, OutcomeIncomplete)
+	}
+	if got := g.Board().WinningLine(); got != nil {
+		t.Errorf("Board().WinningLine() = %v, want nil", got)
+	}
+	if got := g.RenderBoard(); strings.ContainsAny(got, "▸◂▴▾▹◃▵▿") {
+		t.Errorf("RenderBoard() marks a winning line:\n%s", got)
+	}
+}
+
+func TestNineMensMorrisRenderConcurrent(t *testing.T) {
+	// Rendering checks the outcome, which looks for legal moves. That must
+	// not touch the board, so one game can be rendered from several
+	// goroutines.
+	p1, p2 := &Player{}, &Player{}
+	g := NineMensMorris(p1, p2)
+	for _, m := range []string{"a1", "a4", "d1", "b2"} {
+		c, _ := g.board.decodeMove(m)
+		g.board.cells[c.Row][c.Col] = g.Marker(p1)
+	}
+	for _, m := range []string{"g1", "g4", "b4", "f6"} {
+		c, _ := g.board.decodeMove(m)
+		g.board.cells[c.Row][c.Col] = g.Marker(p2)
+	}
+	for i := 0; i < 18; i++ {
+		g.history = append(g.history, Move{Player: []*Player{p1, p2}[i%2]})
+	}
+	want := g.RenderBoard()
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 100)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := g.RenderBoard(); got != want {
+				errs <- got
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for got := range errs {
+		t.Errorf("RenderBoard() rendered concurrently =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+package mnkgame
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// NotaktoRules is tic-tac-toe played on several boards at once where both
+// players place an X. A board is dead once it has three in a row and can't
+// be played on, and the player who kills the last board loses.
+//
+// Moves are the 1-based board number followed by the cell on that board,
+// e.g. "2CC" for the center of the second board.
+//
+// The game is lost by killing the last board rather than won with a line, so
+// the game has no winning line. Each dead board shows its own line.
+var NotaktoRules Rules = notaktoRules{}
+
+type notaktoRules struct{}
+
+// dead reports if the sub-board i, 0-based, has been killed.
+func (notaktoRules) dead(g *MNKGame, i int) bool {
+	return g.board.cells[0][i] != MarkerEmpty
+}
+
+func (r notaktoRules) LegalMoves(g *MNKGame) []string {
+	var moves []string
+	for i, b := range g.boards {
+		if r.dead(g, i) {
+			continue
+		}
+		for _, pos := range b.OpenPositions() {
+			moves = append(moves, strconv.Itoa(i+1)+pos)
+		}
+	}
+	return moves
+}
+
+func (r notaktoRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	i, cell, ok := splitBoardNumber(move, len(g.boards))
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	if r.dead(g, i) {
+		return fmt.Errorf("Board %d is dead", i+1)
+	}
+
+	b := g.boards[i]
+	c, ok := b.decodeMove(cell)
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	if b.cells[c.Row][c.Col] != MarkerEmpty {
+		return fmt.Errorf("Move not available")
+	}
+
+	b.set(c, MarkerX)
+	if b.WinningLine() != nil {
+		g.board.set(Coord{Row: 0, Col: i}, MarkerX)
+	}
+	return nil
+}
+
+func (notaktoRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	if !g.board.full() || len(g.history) == 0 {
+		return OutcomeIncomplete, OutcomeIncomplete
+	}
+
+	// The player who made the last move killed the last board.
+	if g.history[len(g.history)-1].Player == g.player1 {
+		return OutcomeLoss, OutcomeWin
+	}
+	return OutcomeWin, OutcomeLoss
+}
+
+// Score is the number of moves left that do not kill a board, negated, as
+// the player who runs out of them first has to kill a board, and on the last
+// board that loses. Both players place an X, so lines are no help.
+func (r notaktoRules) Score(g *MNKGame, p *Player) int {
+	safe := 0
+	for i, b := range g.boards {
+		if r.dead(g, i) {
+			continue
+		}
+		for row := range b.cells {
+			for col, m := range b.cells[row] {
+				if m == MarkerEmpty && !r.kills(b, Coord{Row: row, Col: col}) {
+					safe++
+				}
+			}
+		}
+	}
+	return -safe
+}
+
+// kills reports if an X in the empty cell c would complete a line on b.
+func (notaktoRules) kills(b *Board, c Coord) bool {
+	for _, line := range b.winTests {
+		if !slices.Contains(line, c) {
+			continue
+		}
+		n := 0
+		for _, d := range line {
+			if d != c && b.cells[d.Row][d.Col] == MarkerX {
+				n++
+			}
+		}
+		if n == len(line)-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// splitBoardNumber splits a move that starts with a 1-based board number,
+// e.g. "2CC", into the 0-based board index and the rest of the move. False
+// is returned if there is no number or it is not one of the n boards.
+func splitBoardNumber(move string, n int) (int, string, bool) {
+	end := 0
+	for end < len(move) && move[end] >= '0' && move[end] <= '9' {
+		end++
+	}
+	i, err := strconv.Atoi(move[:end])
+	if err != nil || i < 1 || i > n {
+		return 0, "", false
+	}
+	return i - 1, move[end:], true
+}
+
+// Notakto returns a new instance of Notakto played on the given number of
+// 3x3 boards. The cells of each board use the tic-tac-toe labels, so a move
+// is written as the board number and the cell, e.g. "1TL".
+//
+// The main board is one row with a cell for each board that is filled once
+// the board is dead.
+func Notakto(boards int, p1, p2 *Player) *MNKGame {
+	g := NewMNKGame("Notakto", 1, boards, boards, NotaktoRules, p1, p2)
+
+	// The cells of the main board only record the dead boards, they do not
+	// make lines.
+	g.board.winTests = nil
+
+	g.addBoards(boards, 3, 3, 3)
+	for _, b := range g.boards {
+		b.SetLabels([]string{"T", "C", "B"}, []string{"L", "C", "R"})
+	}
+
+	return g
+}
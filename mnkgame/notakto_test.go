@@ -0,0 +1,125 @@
+package mnkgame
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNotakto(t *testing.T) {
+	p1, p2 := &Player{}, &Player{}
+	g := Notakto(2, p1, p2)
+
+	if got, want := len(g.PotentialMoves()), 18; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+
+	tests := []struct {
+		move    string
+		wantErr bool
+	}{
+		{move: "3TL", wantErr: true},
+		{move: "0TL", wantErr: true},
+		{move: "TL", wantErr: true},
+		{move: "1", wantErr: true},
+		{move: "1XX", wantErr: true},
+		{move: "1TL"},
+		{move: "1TL", wantErr: true},
+		{move: "1TC"},
+		// Both players place X, so this kills the first board.
+		{move: "1TR"},
+		{move: "1CC", wantErr: true},
+		{move: "2TL"},
+		{move: "2CC"},
+	}
+	for _, test := range tests {
+		err := g.ApplyMove(g.ToMove(), test.move)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ApplyMove(%q) error = %v, want error %v", test.move, err, test.wantErr)
+		}
+	}
+
+	// Only the second board can be played on now.
+	if got, want := len(g.PotentialMoves()), 7; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+	if got := g.Outcome(p1); got != OutcomeIncomplete {
+		t.Errorf("Outcome(p1) = %v, want %v", got, OutcomeIncomplete)
+	}
+
+	// Player 2 kills the last board and loses.
+	if err := g.ApplyMove(p2, "2BR"); err != nil {
+		t.Fatalf("ApplyMove(%q) error = %v", "2BR", err)
+	}
+	if p1Got, p2Got := g.Outcomes(); p1Got != OutcomeWin || p2Got != OutcomeLoss {
+		t.Errorf("Outcomes() = %v, %v, want %v, %v", p1Got, p2Got, OutcomeWin, OutcomeLoss)
+	}
+
+	// The game is lost rather than won with a line, but the dead boards
+	// still show theirs.
+	if got := g.WinningLine(); got != nil {
+		t.Errorf("WinningLine() = %v, want nil", got)
+	}
+	if got := g.Board().WinningLine(); got != nil {
+		t.Errorf("Board().WinningLine() = %v, want nil", got)
+	}
+	for i, v := range g.Boards() {
+		if got := v.WinningLine(); len(got) != 3 {
+			t.Errorf("board %d WinningLine() = %v, want a line of 3", i+1, got)
+		}
+	}
+
+	// The last move is only highlighted on the board it was played on.
+	views := g.Boards()
+	if _, ok := views[0].(lastMover).LastMove(); ok {
+		t.Errorf("board 1 LastMove() = true, want false")
+	}
+	if got, ok := views[1].(lastMover).LastMove(); !ok || got != (Coord{Row: 2, Col: 2}) {
+		t.Errorf("board 2 LastMove() = %v, %v, want %v, true", got, ok, Coord{Row: 2, Col: 2})
+	}
+
+	// Undo brings the board back to life.
+	g.Undo()
+	if got := g.Outcome(p1); got != OutcomeIncomplete {
+		t.Errorf("after Undo, Outcome(p1) = %v, want %v", got, OutcomeIncomplete)
+	}
+	if got := g.board.cells[0][1]; got != MarkerEmpty {
+		t.Errorf("after Undo, board 2 state = %q, want %q", got, MarkerEmpty)
+	}
+}
+
+func TestNotaktoLoser(t *testing.T) {
+	// The player who kills the last board loses, even if they played out
+	// of turn.
+	p1, p2 := &Player{}, &Player{}
+	g := Notakto(1, p1, p2)
+	for _, m := range []string{"1TL", "1TC", "1TR"} {
+		if err := g.ApplyMove(p1, m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+	if p1Got, p2Got := g.Outcomes(); p1Got != OutcomeLoss || p2Got != OutcomeWin {
+		t.Errorf("Outcomes() = %v, %v, want %v, %v", p1Got, p2Got, OutcomeLoss, OutcomeWin)
+	}
+}
+
+func TestNotaktoRender(t *testing.T) {
+	g := Notakto(3, &Player{}, &Player{})
+	g.ApplyMove(g.ToMove(), "2CC")
+
+	single := strings.Split(strings.TrimRight(TicTacToe(Player1, Player2).RenderBoard(), "\n"), "\n")
+	got := strings.Split(strings.TrimRight(g.RenderBoard(), "\n"), "\n")
+	if len(got) != len(single) {
+		t.Fatalf("RenderBoard() has %d lines, want %d", len(got), len(single))
+	}
+
+	// Each line is the three boards' lines next to each other.
+	width := visibleWidth(single[0])
+	for i, line := range got {
+		if want := 3*width + 2*sideBySideGap; visibleWidth(line) != want {
+			t.Errorf("RenderBoard() line %d is %d wide, want %d:\n%s", i, visibleWidth(line), want, line)
+		}
+	}
+	if got, want := strings.Count(got[0], cornerTopLeftThick), 3; got != want {
+		t.Errorf("RenderBoard() has %d boards, want %d", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+package mnkgame
+
+// penteCapturesToWin is the number of pairs a player must capture to win.
+const penteCapturesToWin = 5
+
+// PenteRules is Gomoku with custodial captures. Placing a stone so that
+// exactly two of the opponent's stones in a line are flanked by two of the
+// players stones removes the pair. A player wins with five or more in a
+// row or by capturing five pairs.
+var PenteRules Rules = penteRules{}
+
+// penteDirections are the eight directions a pair can be flanked in.
+var penteDirections = [][2]int{
+	{-1, -1}, {-1, 0}, {-1, 1},
+	{0, -1}, {0, 1},
+	{1, -1}, {1, 0}, {1, 1},
+}
+
+type penteRules struct{}
+
+func (penteRules) LegalMoves(g *MNKGame) []string {
+	return g.board.OpenPositions()
+}
+
+func (penteRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	if err := g.board.ApplyMove(g.Marker(p), move); err != nil {
+		return err
+	}
+	c, _ := g.board.decodeMove(move)
+
+	// Only the stone just placed can flank a pair. A stone played between
+	// two of the opponent's stones is not captured.
+	b := g.board
+	mine, opponent := g.Marker(p), g.Marker(g.opponent(p))
+	for _, d := range penteDirections {
+		first := Coord{Row: c.Row + d[0], Col: c.Col + d[1]}
+		second := Coord{Row: c.Row + 2*d[0], Col: c.Col + 2*d[1]}
+		if b.markerAt(first.Row, first.Col) == opponent &&
+			b.markerAt(second.Row, second.Col) == opponent &&
+			b.markerAt(c.Row+3*d[0], c.Col+3*d[1]) == mine {
+			b.set(first, MarkerEmpty)
+			b.set(second, MarkerEmpty)
+		}
+	}
+	return nil
+}
+
+func (penteRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	if p1, p2 := g.board.Outcome(g.marker1, g.marker2); p1 != OutcomeIncomplete {
+		return p1, p2
+	}
+	if g.Captures(g.player1)/2 >= penteCapturesToWin {
+		return OutcomeWin, OutcomeLoss
+	}
+	if g.Captures(g.player2)/2 >= penteCapturesToWin {
+		return OutcomeLoss, OutcomeWin
+	}
+	return OutcomeIncomplete, OutcomeIncomplete
+}
@@ -0,0 +1,125 @@
+package mnkgame
+
+import "testing"
+
+func TestPenteCaptures(t *testing.T) {
+	tests := []struct {
+		name string
+		// cells already on the board for player 1 and 2.
+		p1, p2 []Coord
+		// move is played by player 1.
+		move         string
+		wantCaptures int
+		wantEmpty    []Coord
+	}{
+		{
+			name:         "flanked pair",
+			p1:           []Coord{{Row: 9, Col: 6}},
+			p2:           []Coord{{Row: 9, Col: 7}, {Row: 9, Col: 8}},
+			move:         "j10",
+			wantCaptures: 2,
+			wantEmpty:    []Coord{{Row: 9, Col: 7}, {Row: 9, Col: 8}},
+		},
+		{
+			name:         "two pairs at once",
+			p1:           []Coord{{Row: 9, Col: 6}, {Row: 6, Col: 9}},
+			p2:           []Coord{{Row: 9, Col: 7}, {Row: 9, Col: 8}, {Row: 7, Col: 9}, {Row: 8, Col: 9}},
+			move:         "j10",
+			wantCaptures: 4,
+			wantEmpty:    []Coord{{Row: 9, Col: 7}, {Row: 9, Col: 8}, {Row: 7, Col: 9}, {Row: 8, Col: 9}},
+		},
+		{
+			name:         "diagonal pair",
+			p1:           []Coord{{Row: 12, Col: 12}},
+			p2:           []Coord{{Row: 10, Col: 10}, {Row: 11, Col: 11}},
+			move:         "j10",
+			wantCaptures: 2,
+			wantEmpty:    []Coord{{Row: 10, Col: 10}, {Row: 11, Col: 11}},
+		},
+		{
+			// Only pairs are captured, not three.
+			name: "three stones",
+			p1:   []Coord{{Row: 9, Col: 5}},
+			p2:   []Coord{{Row: 9, Col: 6}, {Row: 9, Col: 7}, {Row: 9, Col: 8}},
+			move: "j10",
+		},
+		{
+			// Playing between two of the opponent's stones is safe.
+			name: "into a flanked spot",
+			p1:   []Coord{{Row: 9, Col: 8}},
+			p2:   []Coord{{Row: 9, Col: 7}, {Row: 9, Col: 10}},
+			move: "j10",
+		},
+	}
+
+	for _, test := range tests {
+		p1, p2 := &Player{}, &Player{}
+		g := Pente(p1, p2)
+		for _, c := range test.p1 {
+			g.board.cells[c.Row][c.Col] = g.Marker(p1)
+		}
+		for _, c := range test.p2 {
+			g.board.cells[c.Row][c.Col] = g.Marker(p2)
+		}
+
+		if err := g.ApplyMove(p1, test.move); err != nil {
+			t.Errorf("%s: ApplyMove(%q) error = %v", test.name, test.move, err)
+			continue
+		}
+		if got := g.Captures(p1); got != test.wantCaptures {
+			t.Errorf("%s: Captures(p1) = %d, want %d", test.name, got, test.wantCaptures)
+		}
+		for _, c := range test.wantEmpty {
+			if got := g.board.cells[c.Row][c.Col]; got != MarkerEmpty {
+				t.Errorf("%s: cell %v = %q, want %q", test.name, c, got, MarkerEmpty)
+			}
+		}
+
+		// Undo puts the captured stones back.
+		g.Undo()
+		for _, c := range test.p2 {
+			if got := g.board.cells[c.Row][c.Col]; got != g.Marker(p2) {
+				t.Errorf("%s: after Undo cell %v = %q, want %q", test.name, c, got, g.Marker(p2))
+			}
+		}
+	}
+}
+
+func TestPenteOutcome(t *testing.T) {
+	p1, p2 := &Player{}, &Player{}
+	g := Pente(p1, p2)
+
+	// Set up five pairs for player 1 to capture down the left side of the
+	// board, one every other row.
+	for k := 0; k < penteCapturesToWin; k++ {
+		g.board.cells[2*k][0] = g.Marker(p1)
+		g.board.cells[2*k][1] = g.Marker(p2)
+		g.board.cells[2*k][2] = g.Marker(p2)
+	}
+
+	for k := 0; k < penteCapturesToWin; k++ {
+		if got := g.Outcome(p1); got != OutcomeIncomplete {
+			t.Fatalf("after %d captures, Outcome(p1) = %v, want %v", k, got, OutcomeIncomplete)
+		}
+		move := g.board.rowLabels[2*k] + "4"
+		if err := g.ApplyMove(p1, move); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", move, err)
+		}
+		if k < penteCapturesToWin-1 {
+			move = "s" + g.board.colLabels[k]
+			if err := g.ApplyMove(p2, move); err != nil {
+				t.Fatalf("ApplyMove(%q) error = %v", move, err)
+			}
+		}
+	}
+
+	if got, want := g.Captures(p1), 2*penteCapturesToWin; got != want {
+		t.Errorf("Captures(p1) = %d, want %d", got, want)
+	}
+	if got := g.Outcome(p1); got != OutcomeWin {
+		t.Errorf("Outcome(p1) = %v, want %v", got, OutcomeWin)
+	}
+	if got := g.Outcome(p2); got != OutcomeLoss {
+		t.Errorf("Outcome(p2) = %v, want %v", got, OutcomeLoss)
+	}
+}
@@ -1,5 +1,11 @@
 package mnkgame
 
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
 type playerType int
 
 const (
@@ -17,16 +23,56 @@ type Player struct {
 	playerType playerType
 
 	marker Marker
+
+	// chooser picks this players moves. It is only changed by the setters
+	// so that a player shared between games is never written to while
+	// choosing a move.
+	chooser MoveChooser
 }
 
-// SetHuman updates the player type to be a human.
+// SetHuman updates the player type to be a human reading moves from STDIN.
 func (p *Player) SetHuman() {
 	p.playerType = playerTypeHuman
+	p.chooser = NewHumanChooser(os.Stdin, os.Stdout)
 }
 
 // SetComputer sets the player type to be a computer making random moves.
 func (p *Player) SetComputer() {
 	p.playerType = playerTypeComputerRandom
+	p.chooser = NewRandomChooser(nil)
+}
+
+// SetAI sets the player type to be a computer that picks its moves using
+// the given chooser. If chooser is nil, an AIChooser is used.
+func (p *Player) SetAI(chooser MoveChooser) {
+	if chooser == nil {
+		chooser = NewAIChooser(nil)
+	}
+	p.playerType = playerTypeComputerAI
+	p.chooser = chooser
+}
+
+// SetMoveChooser replaces the strategy used to pick this players moves
+// without changing the player type.
+func (p *Player) SetMoveChooser(chooser MoveChooser) {
+	p.chooser = chooser
+}
+
+// ChooseMove asks this players strategy for their next move in the game.
+// Human players have no strategy until SetHuman or SetMoveChooser is called,
+// so that nothing reads from STDIN unless asked to.
+func (p *Player) ChooseMove(ctx context.Context, game *MNKGame) (string, error) {
+	if p.chooser == nil {
+		return "", fmt.Errorf("%s has no way to choose a move", p)
+	}
+	return p.chooser.ChooseMove(ctx, game)
+}
+
+// Marker returns the marker this player places on the board. A game may give
+// the player a different marker, such as when both players have the same
+// one, see MNKGame.Marker.
+func (p *Player) Marker() Marker {
+	return p.marker
 }
 
 func (p *Player) String() string {
@@ -54,6 +100,7 @@ var (
 		displayName: "Computer Player Player 1",
 		marker:      MarkerWhiteStone,
 		playerType:  playerTypeComputerRandom,
+		chooser:     NewRandomChooser(nil),
 	}
 
 	PlayerComputer2 = &Player{
@@ -61,8 +108,6 @@ var (
 		displayName: "Computer Player Player 2",
 		marker:      MarkerBlackStone,
 		playerType:  playerTypeComputerRandom,
+		chooser:     NewRandomChooser(nil),
 	}
 )
-
-// TODO(rsned): Add in some mechanism for the player to choose its move, be it a
-// human reading from STDIN, or a computer player uise rand.Intn(), etc.
@@ -0,0 +1,130 @@
+package mnkgame
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// BoardView is a read-only view of the state of a board, giving renderers
+// access to the cells, labels, and dimensions without being able to change
+// them.
+type BoardView interface {
+	// Rows returns the number of rows on the board.
+	Rows() int
+	// Cols returns the number of columns on the board.
+	Cols() int
+	// Cell returns the marker in the given cell.
+	Cell(row, col int) Marker
+
+	// HasLabels reports if the board has notation labels set.
+	HasLabels() bool
+	// RowLabels returns the notation labels for the rows, if any.
+	RowLabels() []string
+	// ColLabels returns the notation labels for the columns, if any.
+	ColLabels() []string
+
+	// WinningLine returns the cells of the completed winning line, if any.
+	WinningLine() Coords
+}
+
+// Renderer draws a board in some output format.
+type Renderer interface {
+	// Render writes the board to w.
+	Render(w io.Writer, b BoardView) error
+}
+
+// lastMover is implemented by board views that know which cell was played
+// most recently, such as the view of an MNKGame.
+type lastMover interface {
+	LastMove() (Coord, bool)
+}
+
+// multiRenderer is implemented by renderers that can draw all the boards of
+// a game played on several boards together, laid out in rows.
+type multiRenderer interface {
+	RenderBoards(w io.Writer, rows [][]BoardView) error
+}
+
+// sideBySideGap is the number of spaces between boards drawn side by side.
+const sideBySideGap = 2
+
+// ansiEscape matches the ANSI escape sequences that take up no space when
+// text is displayed.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// renderBoardRows draws each of the views with r and lays the text out with
+// the boards in each row next to each other, from left to right, and a blank
+// line between the rows.
+func renderBoardRows(w io.Writer, r Renderer, rows [][]BoardView) error {
+	for i, views := range rows {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := renderSideBySide(w, r, views); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderSideBySide draws each of the views with r and lays the text out with
+// the boards next to each other, from left to right.
+func renderSideBySide(w io.Writer, r Renderer, views []BoardView) error {
+	var blocks []string
+	for _, v := range views {
+		var buf bytes.Buffer
+		if err := r.Render(&buf, v); err != nil {
+			return err
+		}
+		blocks = append(blocks, buf.String())
+	}
+	_, err := io.WriteString(w, joinSideBySide(blocks))
+	return err
+}
+
+// joinSideBySide lays out the blocks of text next to each other, from left
+// to right, padding each to the width of its longest line.
+func joinSideBySide(blocks []string) string {
+	var columns [][]string
+	var widths []int
+	height := 0
+	for _, b := range blocks {
+		lines := strings.Split(strings.TrimRight(b, "\n"), "\n")
+		width := 0
+		for _, l := range lines {
+			width = max(width, visibleWidth(l))
+		}
+		columns = append(columns, lines)
+		widths = append(widths, width)
+		height = max(height, len(lines))
+	}
+
+	var buf strings.Builder
+	for i := 0; i < height; i++ {
+		var line strings.Builder
+		for j, lines := range columns {
+			var l string
+			if i < len(lines) {
+				l = lines[i]
+			}
+			if j < len(columns)-1 {
+				l += spaces(widths[j] - visibleWidth(l) + sideBySideGap)
+			}
+			line.WriteString(l)
+		}
+		buf.WriteString(strings.TrimRight(line.String(), " "))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// visibleWidth returns the number of characters in s when displayed,
+// ignoring any ANSI escape sequences.
+func visibleWidth(s string) int {
+	return utf8.RuneCountInString(ansiEscape.ReplaceAllString(s, ""))
+}
@@ -0,0 +1,99 @@
+package mnkgame
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used to style the board.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiReverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiYellow  = "\x1b[33m"
+	ansiCyan    = "\x1b[36m"
+
+	// emptyCellDot is drawn in empty cells so the dimming is visible.
+	emptyCellDot = "·" // U+00B7 MIDDLE DOT
+)
+
+// ANSIRenderer draws the same box drawing layout as the TextRenderer, with
+// terminal color codes to give each players markers their own color, dim
+// the empty cells, and highlight the most recently played cell.
+type ANSIRenderer struct {
+	// Color turns on the escape codes. When false, the output is exactly
+	// the same as the TextRenderer for dumb terminals.
+	Color bool
+
+	// MarkerColors are the escape sequences used to draw each marker.
+	// Markers not in the map are drawn without color.
+	MarkerColors map[Marker]string
+
+	text *TextRenderer
+}
+
+// NewANSIRenderer returns an ANSI renderer using the given layout options.
+// Color is turned on if the environment supports it.
+func NewANSIRenderer(opts BoardOptions) *ANSIRenderer {
+	return &ANSIRenderer{
+		Color: ColorSupported(),
+		MarkerColors: map[Marker]string{
+			MarkerX:          ansiBold + ansiRed,
+			MarkerWhiteStone: ansiBold + ansiCyan,
+			MarkerBlackStone: ansiBold + ansiYellow,
+		},
+		text: NewTextRenderer(opts),
+	}
+}
+
+// ColorSupported reports if the terminal should be sent color codes. Color is
+// off if the NO_COLOR environment variable is set (see https://no-color.org)
+// or if TERM is unset or "dumb".
+func ColorSupported() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	term := os.Getenv("TERM")
+	return term != "" && term != "dumb"
+}
+
+// Render writes the colored layout of the board to w.
+func (a *ANSIRenderer) Render(w io.Writer, v BoardView) error {
+	if !a.Color {
+		return a.text.Render(w, v)
+	}
+
+	last, hasLast := Coord{}, false
+	if lm, ok := v.(lastMover); ok {
+		last, hasLast = lm.LastMove()
+	}
+
+	style := func(c Coord, m Marker, left, marker, right string) string {
+		var seq string
+		if m == MarkerEmpty {
+			// Swap in the dot for the empty marker, they are both one wide.
+			marker = emptyCellDot + strings.TrimPrefix(marker, string(MarkerEmpty))
+			seq = ansiDim
+		} else {
+			seq = a.MarkerColors[m]
+		}
+		if hasLast && c.equals(last) {
+			seq += ansiReverse
+		}
+		if seq == "" {
+			return left + marker + right
+		}
+		return seq + left + marker + right + ansiReset
+	}
+
+	return a.text.renderWithOptions(w, v, a.text.boardOptions(), style)
+}
+
+// RenderBoards writes the rows of boards to w, with the boards in each row
+// side by side.
+func (a *ANSIRenderer) RenderBoards(w io.Writer, rows [][]BoardView) error {
+	return renderBoardRows(w, a, rows)
+}
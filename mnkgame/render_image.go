@@ -0,0 +1,274 @@
+package mnkgame
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+)
+
+// Indexes into imagePalette for the parts of a drawn board.
+const (
+	imageBackground uint8 = iota
+	imageGrid
+	imageLastMove
+	imageX
+	imageWhiteStone
+	imageBlackStone
+	imageWinLine
+	imageBlocked
+)
+
+// imagePalette is shared by the PNG and GIF images so that every frame of
+// an animation uses the same colors.
+var imagePalette = color.Palette{
+	color.RGBA{0xf4, 0xe4, 0xbc, 0xff},
+	color.RGBA{0x33, 0x33, 0x33, 0xff},
+	color.RGBA{0xff, 0xf0, 0x80, 0xff},
+	color.RGBA{0xbb, 0x22, 0x22, 0xff},
+	color.RGBA{0xff, 0xff, 0xff, 0xff},
+	color.RGBA{0x00, 0x00, 0x00, 0xff},
+	color.RGBA{0x22, 0xaa, 0x22, 0xff},
+	color.RGBA{0xc8, 0xb8, 0x8f, 0xff},
+}
+
+// ImageRenderer draws a board as a raster image. Render writes a PNG, and
+// RenderGIF replays a games history as an animated GIF.
+//
+// Only the standard library image packages are used, so the labels are not
+// drawn.
+//
+// Games played on several boards are drawn with all of their sub-boards laid
+// out together, both by MNKGame.Render and in each frame of RenderGIF.
+type ImageRenderer struct {
+	// CellSize is the width and height of each cell in pixels.
+	CellSize int
+
+	// ShowWinningLine draws a stroke through the cells of a winning line.
+	ShowWinningLine bool
+
+	// ShowLastMove shades the cell of the most recent move, if the board
+	// being drawn knows it.
+	ShowLastMove bool
+
+	// FrameDelay is the time each frame of a GIF is shown for, in 100ths of
+	// a second. The final frame is shown for three times as long.
+	FrameDelay int
+}
+
+// NewImageRenderer returns an image renderer with 40 pixel cells that
+// shows the winning line and the last move, with one second GIF frames.
+func NewImageRenderer() *ImageRenderer {
+	return &ImageRenderer{
+		CellSize:        40,
+		ShowWinningLine: true,
+		ShowLastMove:    true,
+		FrameDelay:      100,
+	}
+}
+
+// Render writes the board to w as a PNG image.
+func (r *ImageRenderer) Render(w io.Writer, v BoardView) error {
+	return png.Encode(w, r.Image(v))
+}
+
+// RenderBoards writes the boards to w as a single PNG image, with the boards
+// in each row next to each other from left to right.
+func (r *ImageRenderer) RenderBoards(w io.Writer, rows [][]BoardView) error {
+	return png.Encode(w, r.boardsImage(rows))
+}
+
+// RenderGIF writes the moves played in the game to w as an animated GIF
+// with one frame per move. The game itself is not changed.
+func (r *ImageRenderer) RenderGIF(w io.Writer, g *MNKGame) error {
+	n := g.MovesPlayed()
+	if n == 0 {
+		return fmt.Errorf("No moves to replay")
+	}
+
+	// Take a copy back to the start and step forward through the moves.
+	replay := g.Clone()
+	for i := 0; i < n; i++ {
+		if err := replay.Undo(); err != nil {
+			return err
+		}
+	}
+
+	anim := &gif.GIF{}
+	for i := 0; i < n; i++ {
+		if err := replay.Redo(); err != nil {
+			return err
+		}
+		delay := r.FrameDelay
+		if i == n-1 {
+			delay *= 3
+		}
+		anim.Image = append(anim.Image, r.frame(replay))
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// frame draws the current state of the game, using the sub-boards of a game
+// played on several boards.
+func (r *ImageRenderer) frame(g *MNKGame) *image.Paletted {
+	if len(g.boards) == 0 {
+		return r.Image(g.Board())
+	}
+	return r.boardsImage(g.boardRows())
+}
+
+// boardsImage draws each of the views and lays them out in one image, with
+// the boards in each row next to each other from left to right. Each board
+// keeps its own margin, which separates it from its neighbors.
+func (r *ImageRenderer) boardsImage(rows [][]BoardView) *image.Paletted {
+	var images [][]*image.Paletted
+	width, height := 0, 0
+	for _, views := range rows {
+		var row []*image.Paletted
+		w, h := 0, 0
+		for _, v := range views {
+			img := r.Image(v)
+			row = append(row, img)
+			w += img.Bounds().Dx()
+			h = max(h, img.Bounds().Dy())
+		}
+		images = append(images, row)
+		width = max(width, w)
+		height += h
+	}
+
+	out := image.NewPaletted(image.Rect(0, 0, width, height), imagePalette)
+	y := 0
+	for _, row := range images {
+		x, h := 0, 0
+		for _, img := range row {
+			b := img.Bounds()
+			draw.Draw(out, b.Add(image.Pt(x, y)), img, b.Min, draw.Src)
+			x += b.Dx()
+			h = max(h, b.Dy())
+		}
+		y += h
+	}
+	return out
+}
+
+// Image draws the board into a new paletted image.
+func (r *ImageRenderer) Image(v BoardView) *image.Paletted {
+	cell := r.CellSize
+	if cell <= 0 {
+		cell = 40
+	}
+	margin := cell / 4
+	rows, cols := v.Rows(), v.Cols()
+	img := image.NewPaletted(image.Rect(0, 0, 2*margin+cols*cell, 2*margin+rows*cell), imagePalette)
+
+	// center returns the pixel coordinates of the middle of a cell.
+	center := func(row, col int) image.Point {
+		return image.Pt(margin+col*cell+cell/2, margin+row*cell+cell/2)
+	}
+
+	if lm, ok := v.(lastMover); ok && r.ShowLastMove {
+		if c, ok := lm.LastMove(); ok {
+			x, y := margin+c.Col*cell, margin+c.Row*cell
+			fillRect(img, image.Rect(x, y, x+cell, y+cell), imageLastMove)
+		}
+	}
+
+	// Grid lines.
+	for i := 0; i <= rows; i++ {
+		y := margin + i*cell
+		fillRect(img, image.Rect(margin, y, margin+cols*cell+1, y+1), imageGrid)
+	}
+	for j := 0; j <= cols; j++ {
+		x := margin + j*cell
+		fillRect(img, image.Rect(x, margin, x+1, margin+rows*cell+1), imageGrid)
+	}
+
+	// Markers.
+	radius := float64(cell) * 3 / 8
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			c := center(i, j)
+			switch m := v.Cell(i, j); m {
+			case MarkerEmpty:
+			case MarkerBlocked:
+				fillRect(img, image.Rect(c.X-cell/2+1, c.Y-cell/2+1, c.X+cell/2, c.Y+cell/2), imageBlocked)
+			case MarkerX:
+				d := int(radius)
+				w := max(float64(cell)/20, 1)
+				drawLine(img, c.Add(image.Pt(-d, -d)), c.Add(image.Pt(d, d)), w, imageX)
+				drawLine(img, c.Add(image.Pt(-d, d)), c.Add(image.Pt(d, -d)), w, imageX)
+			case MarkerWhiteStone:
+				fillCircle(img, c, radius, imageGrid)
+				fillCircle(img, c, radius-1, imageWhiteStone)
+			case MarkerBlackStone:
+				fillCircle(img, c, radius, imageBlackStone)
+			default:
+				// Any other marker is drawn as a plain square.
+				d := int(radius / 2)
+				fillRect(img, image.Rect(c.X-d, c.Y-d, c.X+d, c.Y+d), imageGrid)
+			}
+		}
+	}
+
+	// Winning line, from the center of the first cell to the center of the last.
+	if line := v.WinningLine(); r.ShowWinningLine && len(line) > 0 {
+		first, last := line[0], line[len(line)-1]
+		drawLine(img, center(first.Row, first.Col), center(last.Row, last.Col),
+			max(float64(cell)/16, 1), imageWinLine)
+	}
+
+	return img
+}
+
+// fillRect sets every pixel in the rectangle to the given palette index.
+func fillRect(img *image.Paletted, rect image.Rectangle, idx uint8) {
+	rect = rect.Intersect(img.Bounds())
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetColorIndex(x, y, idx)
+		}
+	}
+}
+
+// fillCircle sets every pixel within radius of c to the given palette index.
+func fillCircle(img *image.Paletted, c image.Point, radius float64, idx uint8) {
+	d := int(radius) + 1
+	for y := c.Y - d; y <= c.Y+d; y++ {
+		for x := c.X - d; x <= c.X+d; x++ {
+			dx, dy := float64(x-c.X), float64(y-c.Y)
+			if dx*dx+dy*dy <= radius*radius && (image.Point{x, y}).In(img.Bounds()) {
+				img.SetColorIndex(x, y, idx)
+			}
+		}
+	}
+}
+
+// drawLine sets every pixel within half the given width of the segment from
+// a to b to the given palette index.
+func drawLine(img *image.Paletted, a, b image.Point, width float64, idx uint8) {
+	half := width / 2
+	pad := int(half) + 1
+	bounds := image.Rect(min(a.X, b.X)-pad, min(a.Y, b.Y)-pad, max(a.X, b.X)+pad+1, max(a.Y, b.Y)+pad+1).Intersect(img.Bounds())
+
+	dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+	length := dx*dx + dy*dy
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Find the closest point on the segment to this pixel.
+			px, py := float64(x-a.X), float64(y-a.Y)
+			t := 0.0
+			if length > 0 {
+				t = min(max((px*dx+py*dy)/length, 0), 1)
+			}
+			ex, ey := px-t*dx, py-t*dy
+			if ex*ex+ey*ey <= half*half {
+				img.SetColorIndex(x, y, idx)
+			}
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package mnkgame
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LayeredRenderer draws the boards of a game played on several boards, such
+// as the layers of a three dimensional game, one after another with a heading
+// giving the number used for each in move notation.
+type LayeredRenderer struct {
+	// Renderer draws each of the boards.
+	Renderer Renderer
+
+	// SideBySide lays the boards out from left to right instead of from top
+	// to bottom.
+	SideBySide bool
+}
+
+// NewLayeredRenderer returns a layered renderer that draws each board with r,
+// from top to bottom.
+func NewLayeredRenderer(r Renderer) *LayeredRenderer {
+	return &LayeredRenderer{Renderer: r}
+}
+
+// Render writes a single board to w.
+func (l *LayeredRenderer) Render(w io.Writer, v BoardView) error {
+	return l.Renderer.Render(w, v)
+}
+
+// RenderBoards writes all the boards to w, numbered from 1 in order.
+func (l *LayeredRenderer) RenderBoards(w io.Writer, rows [][]BoardView) error {
+	var blocks []string
+	for _, views := range rows {
+		for _, v := range views {
+			var buf bytes.Buffer
+			fmt.Fprintf(&buf, "Layer %d\n", len(blocks)+1)
+			if err := l.Renderer.Render(&buf, v); err != nil {
+				return err
+			}
+			blocks = append(blocks, buf.String())
+		}
+	}
+
+	if l.SideBySide {
+		_, err := io.WriteString(w, joinSideBySide(blocks))
+		return err
+	}
+	_, err := io.WriteString(w, strings.Join(blocks, "\n"))
+	return err
+}
@@ -0,0 +1,124 @@
+package mnkgame
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+)
+
+// SVGRenderer draws a board as a scalable vector graphics image, such as for
+// showing results in a web page.
+type SVGRenderer struct {
+	// CellSize is the width and height of each cell in pixels.
+	CellSize int
+
+	// ShowLabels draws the row and column labels, if the board has them,
+	// along the left and top edges.
+	ShowLabels bool
+
+	// ShowWinningLine draws a stroke through the cells of a winning line.
+	ShowWinningLine bool
+}
+
+// NewSVGRenderer returns an SVG renderer with 40 pixel cells that shows the
+// labels and the winning line.
+func NewSVGRenderer() *SVGRenderer {
+	return &SVGRenderer{
+		CellSize:        40,
+		ShowLabels:      true,
+		ShowWinningLine: true,
+	}
+}
+
+// Render writes the board to w as an SVG document.
+func (s *SVGRenderer) Render(w io.Writer, v BoardView) error {
+	cell := s.CellSize
+	if cell <= 0 {
+		cell = 40
+	}
+	rows, cols := v.Rows(), v.Cols()
+	labels := s.ShowLabels && v.HasLabels()
+
+	// Leave room around the board for the labels or a small border.
+	margin := cell / 4
+	if labels {
+		margin = cell
+	}
+	width := 2*margin + cols*cell
+	height := 2*margin + rows*cell
+
+	// center returns the pixel coordinates of the middle of a cell.
+	center := func(row, col int) (int, int) {
+		return margin + col*cell + cell/2, margin + row*cell + cell/2
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#f4e4bc"/>`+"\n", width, height)
+
+	// Grid lines.
+	buf.WriteString(`<g stroke="#333" stroke-width="1">` + "\n")
+	for i := 0; i <= rows; i++ {
+		y := margin + i*cell
+		fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", margin, y, margin+cols*cell, y)
+	}
+	for j := 0; j <= cols; j++ {
+		x := margin + j*cell
+		fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", x, margin, x, margin+rows*cell)
+	}
+	buf.WriteString("</g>\n")
+
+	// Labels.
+	if labels {
+		fmt.Fprintf(&buf, `<g font-family="sans-serif" font-size="%d" fill="#333" text-anchor="middle" dominant-baseline="central">`+"\n",
+			cell/2)
+		for i, l := range v.RowLabels() {
+			_, y := center(i, 0)
+			fmt.Fprintf(&buf, `<text x="%d" y="%d">%s</text>`+"\n", margin/2, y, html.EscapeString(l))
+		}
+		for j, l := range v.ColLabels() {
+			x, _ := center(0, j)
+			fmt.Fprintf(&buf, `<text x="%d" y="%d">%s</text>`+"\n", x, margin/2, html.EscapeString(l))
+		}
+		buf.WriteString("</g>\n")
+	}
+
+	// Markers.
+	r := cell * 3 / 8
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			x, y := center(i, j)
+			switch m := v.Cell(i, j); m {
+			case MarkerEmpty:
+			case MarkerBlocked:
+				fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="#c8b88f"/>`+"\n",
+					x-cell/2, y-cell/2, cell, cell)
+			case MarkerX:
+				fmt.Fprintf(&buf, `<path d="M%d %d L%d %d M%d %d L%d %d" stroke="#b22" stroke-width="%d" stroke-linecap="round"/>`+"\n",
+					x-r, y-r, x+r, y+r, x-r, y+r, x+r, y-r, max(cell/10, 1))
+			case MarkerWhiteStone:
+				fmt.Fprintf(&buf, `<circle cx="%d" cy="%d" r="%d" fill="#fff" stroke="#000" stroke-width="1"/>`+"\n", x, y, r)
+			case MarkerBlackStone:
+				fmt.Fprintf(&buf, `<circle cx="%d" cy="%d" r="%d" fill="#000"/>`+"\n", x, y, r)
+			default:
+				// Any other marker is drawn as its text.
+				fmt.Fprintf(&buf, `<text x="%d" y="%d" font-size="%d" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+					x, y, cell/2, html.EscapeString(string(m)))
+			}
+		}
+	}
+
+	// Winning line, from the center of the first cell to the center of the last.
+	if line := v.WinningLine(); s.ShowWinningLine && len(line) > 0 {
+		x1, y1 := center(line[0].Row, line[0].Col)
+		x2, y2 := center(line[len(line)-1].Row, line[len(line)-1].Col)
+		fmt.Fprintf(&buf, `<line class="win" x1="%d" y1="%d" x2="%d" y2="%d" stroke="#2a2" stroke-width="%d" stroke-linecap="round" stroke-opacity="0.7"/>`+"\n",
+			x1, y1, x2, y2, max(cell/8, 1))
+	}
+
+	buf.WriteString("</svg>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,441 @@
+package mnkgame
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// stubView is a minimal BoardView that is not backed by a Board.
+type stubView struct {
+	cells     [][]Marker
+	rowLabels []string
+	colLabels []string
+}
+
+func (s *stubView) Rows() int                { return len(s.cells) }
+func (s *stubView) Cols() int                { return len(s.cells[0]) }
+func (s *stubView) Cell(row, col int) Marker { return s.cells[row][col] }
+func (s *stubView) HasLabels() bool          { return len(s.rowLabels) > 0 }
+func (s *stubView) RowLabels() []string      { return s.rowLabels }
+func (s *stubView) ColLabels() []string      { return s.colLabels }
+func (s *stubView) WinningLine() Coords      { return nil }
+
+func TestTextRenderer(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	game.ApplyMove(Player1, "TL")
+	game.ApplyMove(Player2, "CC")
+
+	view := &stubView{
+		cells: [][]Marker{
+			[]Marker{MarkerX, MarkerEmpty, MarkerEmpty},
+			[]Marker{MarkerEmpty, MarkerWhiteStone, MarkerEmpty},
+			[]Marker{MarkerEmpty, MarkerEmpty, MarkerEmpty},
+		},
+		rowLabels: []string{"T", "C", "B"},
+		colLabels: []string{"L", "C", "R"},
+	}
+
+	var got bytes.Buffer
+	if err := (&TextRenderer{}).Render(&got, view); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	var want bytes.Buffer
+	if err := game.Render(&want, &TextRenderer{}); err != nil {
+		t.Fatalf("game.Render() error = %v", err)
+	}
+
+	if got.String() != want.String() {
+		t.Errorf("Render(view) =\n%s\nwant\n%s", got.String(), want.String())
+	}
+	if want.String() != game.RenderBoard() {
+		t.Errorf("Render(game) =\n%s\nwant\n%s", want.String(), game.RenderBoard())
+	}
+}
+
+func TestBoardView(t *testing.T) {
+	game := Connect4(Player1, Player2)
+	game.ApplyMove(Player1, "3")
+
+	v := game.Board()
+	if v.Rows() != 6 || v.Cols() != 7 {
+		t.Errorf("Board() dimensions = %dx%d, want 6x7", v.Rows(), v.Cols())
+	}
+	if got := v.Cell(5, 2); got != Player1.Marker() {
+		t.Errorf("Board().Cell(5, 2) = %q, want %q", got, Player1.Marker())
+	}
+
+	// Changing the returned labels must not change the board.
+	labels := v.ColLabels()
+	labels[0] = "Z"
+	if got := v.ColLabels()[0]; got != "1" {
+		t.Errorf("ColLabels()[0] after changing the returned slice = %q, want %q", got, "1")
+	}
+}
+
+func TestRenderWithOptions(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	game.ApplyMove(Player1, "TL")
+	game.ApplyMove(Player2, "BR")
+
+	tests := []struct {
+		opts BoardOptions
+		want string
+	}{
+		{
+			opts: CompactBoardOptions(),
+			want: "" +
+				"    L  C  R    \n" +
+				" T  🗙          \n" +
+				" C             \n" +
+				" B        ⭘    \n" +
+				"    L  C  R    \n",
+		},
+		{
+			opts: BoardOptions{HasInnerBorder: true, HasInnerGrid: true},
+			want: "" +
+				"┌─┬─┬─┐\n" +
+				"│🗙│ │ │\n" +
+				"├─┼─┼─┤\n" +
+				"│ │ │ │\n" +
+				"├─┼─┼─┤\n" +
+				"│ │ │⭘│\n" +
+				"└─┴─┴─┘\n",
+		},
+	}
+
+	for _, test := range tests {
+		if got := game.RenderWithOptions(test.opts); got != test.want {
+			t.Errorf("RenderWithOptions(%+v) =\n%s\nwant\n%s", test.opts, got, test.want)
+		}
+	}
+}
+
+func TestRenderWithOptionsAligned(t *testing.T) {
+	// Every combination of options on boards that are not square should
+	// produce lines of the same width.
+	boards := []*Board{
+		newBoard(2, 5, 2),
+		newBoard(5, 2, 2),
+		newBoard(1, 1, 1),
+	}
+	boards[0].SetLabels([]string{"a", "b"}, []string{"1", "2", "3", "4", "10"})
+	boards[1].SetLabels([]string{"aa", "bb", "cc", "dd", "ee"}, []string{"X", "Y"})
+
+	for _, b := range boards {
+		for mask := 0; mask < 16; mask++ {
+			for padding := 0; padding < 3; padding++ {
+				opts := BoardOptions{
+					HasOuterBorder: mask&1 != 0,
+					HasInnerBorder: mask&2 != 0,
+					HasInnerGrid:   mask&4 != 0,
+					HasLabels:      mask&8 != 0,
+					MarkerWidth:    1,
+					Padding:        padding,
+				}
+				out := b.RenderWithOptions(opts)
+				lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+				if len(lines) < b.rows {
+					t.Errorf("%dx%d RenderWithOptions(%+v) has %d lines, want at least %d",
+						b.rows, b.cols, opts, len(lines), b.rows)
+				}
+				for _, l := range lines {
+					if utf8.RuneCountInString(l) != utf8.RuneCountInString(lines[0]) {
+						t.Errorf("%dx%d RenderWithOptions(%+v) lines are not aligned:\n%s",
+							b.rows, b.cols, opts, out)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRenderWinningLine(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	for _, m := range []string{"TL", "CL", "CC", "BL", "BR"} {
+		game.ApplyMove(game.ToMove(), m)
+	}
+
+	opts := BoardOptions{HasInnerBorder: true, HasInnerGrid: true, Padding: 1, HighlightWin: true}
+	want := "" +
+		"┌───┬───┬───┐\n" +
+		"│▸🗙◂│   │   │\n" +
+		"├───┼───┼───┤\n" +
+		"│ ⭘ │▸🗙◂│   │\n" +
+		"├───┼───┼───┤\n" +
+		"│ ⭘ │   │▸🗙◂│\n" +
+		"└───┴───┴───┘\n"
+	if got := game.RenderWithOptions(opts); got != want {
+		t.Errorf("RenderWithOptions(%+v) =\n%s\nwant\n%s", opts, got, want)
+	}
+
+	// Without the highlight, the cells are drawn as usual.
+	opts.HighlightWin = false
+	if got := game.RenderWithOptions(opts); strings.Contains(got, winMarkerRightArrowBlack) {
+		t.Errorf("RenderWithOptions(%+v) =\n%s\nwant no win markers", opts, got)
+	}
+}
+
+func TestANSIRenderer(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	game.ApplyMove(Player1, "TL")
+	game.ApplyMove(Player2, "CC")
+
+	opts := BoardOptions{Padding: 1}
+	a := NewANSIRenderer(opts)
+
+	// With color off the output matches the plain text.
+	a.Color = false
+	var got bytes.Buffer
+	if err := game.Render(&got, a); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := game.RenderWithOptions(opts); got.String() != want {
+		t.Errorf("Render() without color =\n%q\nwant\n%q", got.String(), want)
+	}
+
+	a.Color = true
+	got.Reset()
+	if err := game.Render(&got, a); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	want := "" +
+		ansiBold + ansiRed + " 🗙 " + ansiReset +
+		ansiDim + " · " + ansiReset +
+		ansiDim + " · " + ansiReset + "\n" +
+		ansiDim + " · " + ansiReset +
+		ansiBold + ansiCyan + ansiReverse + " ⭘ " + ansiReset +
+		ansiDim + " · " + ansiReset + "\n" +
+		ansiDim + " · " + ansiReset +
+		ansiDim + " · " + ansiReset +
+		ansiDim + " · " + ansiReset + "\n"
+	if got.String() != want {
+		t.Errorf("Render() with color =\n%q\nwant\n%q", got.String(), want)
+	}
+}
+
+func TestColorSupported(t *testing.T) {
+	tests := []struct {
+		noColor string
+		term    string
+		want    bool
+	}{
+		{noColor: "", term: "xterm-256color", want: true},
+		{noColor: "1", term: "xterm-256color", want: false},
+		{noColor: "", term: "dumb", want: false},
+		{noColor: "", term: "", want: false},
+	}
+
+	for _, test := range tests {
+		t.Setenv("NO_COLOR", test.noColor)
+		t.Setenv("TERM", test.term)
+		if got := ColorSupported(); got != test.want {
+			t.Errorf("ColorSupported() with NO_COLOR=%q TERM=%q = %v, want %v",
+				test.noColor, test.term, got, test.want)
+		}
+	}
+}
+
+func TestSVGRenderer(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	for _, m := range []string{"TR", "CL", "CC", "BR", "BL"} {
+		game.ApplyMove(game.ToMove(), m)
+	}
+
+	var buf bytes.Buffer
+	if err := game.Render(&buf, NewSVGRenderer()); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	// Count up the elements of the document, which also checks that it is
+	// well formed.
+	counts := map[string]int{}
+	var winLine []xml.Attr
+	d := xml.NewDecoder(&buf)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Render() produced invalid XML: %v", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok {
+			counts[se.Name.Local]++
+			for _, a := range se.Attr {
+				if a.Name.Local == "class" && a.Value == "win" {
+					winLine = se.Attr
+				}
+			}
+		}
+	}
+
+	want := map[string]int{
+		"svg":    1,
+		"rect":   1,
+		"g":      2,
+		"line":   4 + 4 + 1, // Grid lines plus the winning line.
+		"text":   6,         // Row and column labels.
+		"path":   3,         // Player 1 crosses.
+		"circle": 2,         // Player 2 stones.
+	}
+	if !cmp.Equal(counts, want) {
+		t.Errorf("Render() elements = %v, want %v", counts, want)
+	}
+
+	// The winning line runs from the top right to the bottom left.
+	wantWin := map[string]string{"x1": "140", "y1": "60", "x2": "60", "y2": "140"}
+	for _, a := range winLine {
+		if v, ok := wantWin[a.Name.Local]; ok && v != a.Value {
+			t.Errorf("winning line %s = %s, want %s", a.Name.Local, a.Value, v)
+		}
+	}
+	if winLine == nil {
+		t.Errorf("Render() has no winning line")
+	}
+}
+
+func TestImageRenderer(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	for _, m := range []string{"TR", "CL", "CC", "BR", "BL"} {
+		game.ApplyMove(game.ToMove(), m)
+	}
+
+	var buf bytes.Buffer
+	if err := game.Render(&buf, NewImageRenderer()); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Render() produced an invalid PNG: %v", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 140, 140); got != want {
+		t.Errorf("Render() bounds = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		x, y int
+		want uint8
+	}{
+		// Empty cell.
+		{x: 110, y: 60, want: imageBackground},
+		// Grid line.
+		{x: 50, y: 10, want: imageGrid},
+		// Player 2 stone off the winning line.
+		{x: 30, y: 60, want: imageWhiteStone},
+		// Player 1 cross in the corner of its cell, away from the line.
+		{x: 115, y: 25, want: imageX},
+		// The winning line runs through the center.
+		{x: 70, y: 70, want: imageWinLine},
+		// The last move, BL, is shaded.
+		{x: 12, y: 125, want: imageLastMove},
+	}
+	for _, test := range tests {
+		want := imagePalette[test.want]
+		if got := img.At(test.x, test.y); !cmp.Equal(color.RGBAModel.Convert(got), want) {
+			t.Errorf("Render() pixel (%d, %d) = %v, want %v", test.x, test.y, got, want)
+		}
+	}
+}
+
+func TestImageRendererGIF(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	r := NewImageRenderer()
+
+	var buf bytes.Buffer
+	if err := r.RenderGIF(&buf, game); err == nil {
+		t.Errorf("RenderGIF() with no moves = nil, want error")
+	}
+
+	moves := []string{"TR", "CL", "CC", "BR", "BL"}
+	for _, m := range moves {
+		game.ApplyMove(game.ToMove(), m)
+	}
+	before := game.RenderBoard()
+
+	buf.Reset()
+	if err := r.RenderGIF(&buf, game); err != nil {
+		t.Fatalf("RenderGIF() error = %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("RenderGIF() produced an invalid GIF: %v", err)
+	}
+	if got, want := len(anim.Image), len(moves); got != want {
+		t.Errorf("RenderGIF() frames = %d, want %d", got, want)
+	}
+
+	// The first frame has only the first move, in the top right.
+	first := anim.Image[0]
+	if got := first.ColorIndexAt(112, 15); got != imageLastMove {
+		t.Errorf("first frame pixel at TR = %d, want %d", got, imageLastMove)
+	}
+	if got := first.ColorIndexAt(70, 70); got != imageBackground {
+		t.Errorf("first frame pixel at CC = %d, want %d", got, imageBackground)
+	}
+
+	if got := game.RenderBoard(); got != before {
+		t.Errorf("RenderGIF() changed the game board to\n%s\nwant\n%s", got, before)
+	}
+	if got, want := game.MovesPlayed(), len(moves); got != want {
+		t.Errorf("RenderGIF() changed MovesPlayed() to %d, want %d", got, want)
+	}
+}
+
+func TestImageRendererBoards(t *testing.T) {
+	game := Notakto(2, Player1, Player2)
+	for _, m := range []string{"2TL", "1CC"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+	r := NewImageRenderer()
+
+	// Each 3x3 board is 140 pixels square with its margin, side by side.
+	var buf bytes.Buffer
+	if err := game.Render(&buf, r); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Render() produced an invalid PNG: %v", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 280, 140); got != want {
+		t.Errorf("Render() bounds = %v, want %v", got, want)
+	}
+
+	buf.Reset()
+	if err := r.RenderGIF(&buf, game); err != nil {
+		t.Fatalf("RenderGIF() error = %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("RenderGIF() produced an invalid GIF: %v", err)
+	}
+	if got, want := len(anim.Image), 2; got != want {
+		t.Fatalf("RenderGIF() frames = %d, want %d", got, want)
+	}
+
+	// The first frame shows the move on the top left of the second board.
+	first := anim.Image[0]
+	if got, want := first.Bounds(), image.Rect(0, 0, 280, 140); got != want {
+		t.Errorf("first frame bounds = %v, want %v", got, want)
+	}
+	if got := first.ColorIndexAt(152, 15); got != imageLastMove {
+		t.Errorf("first frame pixel at 2TL = %d, want %d", got, imageLastMove)
+	}
+	if got := first.ColorIndexAt(12, 15); got != imageBackground {
+		t.Errorf("first frame pixel at 1TL = %d, want %d", got, imageBackground)
+	}
+}
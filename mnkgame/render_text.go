@@ -0,0 +1,437 @@
+package mnkgame
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// TextRenderer draws a board as fixed width text using box drawing
+// characters for the borders and grid lines.
+//
+// The parts of the output that only depend on the board dimensions and
+// labels are computed once and cached, so a single TextRenderer may be
+// used to render many boards, including from more than one goroutine.
+type TextRenderer struct {
+	// options are the layout settings, or nil for DefaultBoardOptions.
+	options *BoardOptions
+
+	mu    sync.Mutex
+	cache map[renderKey]*staticElements
+}
+
+// NewTextRenderer returns a text renderer using the given layout options.
+func NewTextRenderer(opts BoardOptions) *TextRenderer {
+	return &TextRenderer{options: &opts}
+}
+
+// renderKey identifies the inputs the static elements are generated from.
+type renderKey struct {
+	rows      int
+	cols      int
+	colLabels string
+	options   BoardOptions
+}
+
+// defaultTextRenderer is used for the String and RenderWithOptions methods
+// of boards.
+var defaultTextRenderer = &TextRenderer{}
+
+// BoardOptions packages up the various settings used when rendering the game board.
+type BoardOptions struct {
+	HasOuterBorder bool // Should there be a line around the labels outside the main board.
+	HasInnerBorder bool // Should there be a line around the main board area.
+	HasInnerGrid   bool // Should we render the lines separating each row and column.
+	HasLabels      bool // Do we have labels to show.
+	LabelWidth     int  // Width of longest label to be displayed.
+	MarkerWidth    int  // Width of the widest player marker symbol.
+	Padding        int  // Amount of whitespace on either side of labels and markers.
+	HighlightWin   bool // Should the cells of a winning line be marked with arrows.
+}
+
+// DefaultBoardOptions returns the options used by Board.String, with both
+// borders, the grid lines, and labels if the board has them.
+func DefaultBoardOptions() BoardOptions {
+	return BoardOptions{
+		HasOuterBorder: true,
+		HasInnerBorder: true,
+		HasInnerGrid:   true,
+		HasLabels:      true,
+		MarkerWidth:    1,
+		Padding:        4,
+		HighlightWin:   true,
+	}
+}
+
+// CompactBoardOptions returns options for a small layout without borders or
+// grid lines that fits in narrow terminals.
+func CompactBoardOptions() BoardOptions {
+	return BoardOptions{
+		HasLabels:    true,
+		MarkerWidth:  1,
+		Padding:      1,
+		HighlightWin: true,
+	}
+}
+
+// fitTo adjusts the options to the board being rendered. Labels are only
+// shown if the board has them, and the label and marker widths are widened
+// to fit the longest label so the columns stay aligned.
+func (bo BoardOptions) fitTo(v BoardView) BoardOptions {
+	bo.HasLabels = bo.HasLabels && v.HasLabels()
+	bo.MarkerWidth = max(bo.MarkerWidth, 1)
+	bo.Padding = max(bo.Padding, 0)
+	if bo.HasLabels {
+		bo.LabelWidth = max(bo.LabelWidth, labelWidth(v.RowLabels()))
+		bo.MarkerWidth = max(bo.MarkerWidth, labelWidth(v.ColLabels()))
+	} else {
+		bo.LabelWidth = 0
+	}
+	return bo
+}
+
+// Various board border and separator tokens.
+// See https://en.wikipedia.org/wiki/Box_Drawing for more symbols.
+const (
+	cornerTopLeft          = "┌"
+	cornerTopLeftThick     = "┏"
+	cornerTopRight         = "┐"
+	cornerTopRightThick    = "┓"
+	cornerBottomLeft       = "└"
+	cornerBottomLeftThick  = "┗"
+	cornerBottomRight      = "┘"
+	cornerBottomRightThick = "┛"
+	lineHorizontal         = "─"
+	lineHorizontalThick    = "━"
+	lineVertical           = "│"
+	lineVerticalThick      = "┃"
+	cross                  = "┼"
+	crossThick             = "╋"
+	teeLeft                = "├"
+	teeLeftThick           = "┣"
+	teeRight               = "┤"
+	teeRightThick          = "┫"
+	teeUp                  = "┴"
+	teeUpThick             = "┻"
+	teeDown                = "┬"
+	teeDownThick           = "┳"
+)
+
+// staticElements are the parts of a rendered board that only depend on the
+// board dimensions, labels, and the rendering options, so they can be
+// computed once and reused each time the board is rendered.
+type staticElements struct {
+	topOuterBorder    string
+	botOuterBorder    string
+	colLabels         string
+	topInnerBorder    string
+	bottomInnerBorder string
+	innerSeparator    string
+
+	// rowStart and rowEnd are the left and right outer borders and label
+	// space around the cells of each row, not including the row label.
+	rowStart string
+	rowEnd   string
+}
+
+// generateStaticElements computes the dimensions of the board and renders
+// the parts of the board that don't change every iteration for the rendering
+// throughout the remainder of the run.
+func generateStaticElements(v BoardView, bo *BoardOptions) *staticElements {
+	e := &staticElements{}
+	cols := v.Cols()
+	cellWidth := bo.MarkerWidth + 2*bo.Padding
+	labelSpace := ""
+	if bo.HasLabels {
+		labelSpace = spaces(bo.LabelWidth + 2*bo.Padding)
+	}
+
+	// Figure out the overall width of the output starting with the number of
+	// columns plus padding on either side.
+	boardWidth := cols * cellWidth
+	if bo.HasOuterBorder {
+		boardWidth += utf8.RuneCountInString(cornerTopLeftThick) +
+			utf8.RuneCountInString(cornerTopRightThick)
+	}
+	if bo.HasInnerBorder {
+		boardWidth += utf8.RuneCountInString(cornerTopLeft) +
+			utf8.RuneCountInString(cornerTopRight)
+	}
+	if bo.HasInnerGrid {
+		boardWidth += (cols - 1) * utf8.RuneCountInString(lineVertical)
+	}
+	if bo.HasLabels {
+		// Add spacing to left and right sides of the board.
+		boardWidth += 2 * utf8.RuneCountInString(labelSpace)
+	}
+
+	// -----------------------------------------------
+	// Top and bottom outer border
+	// -----------------------------------------------
+
+	if bo.HasOuterBorder {
+		e.topOuterBorder = cornerTopLeftThick +
+			strings.Repeat(lineHorizontalThick, boardWidth-2) +
+			cornerTopRightThick +
+			"\n"
+
+		e.botOuterBorder = cornerBottomLeftThick +
+			strings.Repeat(lineHorizontalThick, boardWidth-2) +
+			cornerBottomRightThick +
+			"\n"
+	}
+
+	// -----------------------------------------------
+	// Start and end of every line inside the outer border
+	// -----------------------------------------------
+
+	if bo.HasOuterBorder {
+		e.rowStart = lineVerticalThick
+	}
+	e.rowEnd = labelSpace
+	if bo.HasOuterBorder {
+		e.rowEnd += lineVerticalThick
+	}
+	e.rowEnd += "\n"
+
+	// -----------------------------------------------
+	// Top and bottom labels
+	// -----------------------------------------------
+
+	var rowBuf bytes.Buffer
+	rowBuf.WriteString(e.rowStart)
+	rowBuf.WriteString(labelSpace)
+	// Inner border padding
+	if bo.HasInnerBorder {
+		rowBuf.WriteString(" ")
+	}
+	for i, col := range v.ColLabels() {
+		rowBuf.WriteString(spaces(bo.Padding))
+		rowBuf.WriteString(padRight(col, bo.MarkerWidth))
+		rowBuf.WriteString(spaces(bo.Padding))
+		if bo.HasInnerGrid && i != cols-1 {
+			rowBuf.WriteString(" ")
+		}
+	}
+	// Inner border padding
+	if bo.HasInnerBorder {
+		rowBuf.WriteString(" ")
+	}
+	rowBuf.WriteString(e.rowEnd)
+	e.colLabels = rowBuf.String()
+
+	// -----------------------------------------------
+	// Top and bottom inner borders
+	// -----------------------------------------------
+
+	// Without the grid there is no gap between the cells to join to.
+	down, up := "", ""
+	if bo.HasInnerGrid {
+		down, up = teeDown, teeUp
+	}
+	e.topInnerBorder = e.horizontalLine(cols, cellWidth, labelSpace,
+		cornerTopLeft, down, cornerTopRight)
+	e.bottomInnerBorder = e.horizontalLine(cols, cellWidth, labelSpace,
+		cornerBottomLeft, up, cornerBottomRight)
+
+	// -----------------------------------------------
+	// Inner grid separator lines
+	// -----------------------------------------------
+
+	// TODO(rsned): To allow for vertical padding, generate the same row without
+	// the marker values added in.
+
+	left, right := "", ""
+	if bo.HasInnerBorder {
+		left, right = teeLeft, teeRight
+	}
+	e.innerSeparator = e.horizontalLine(cols, cellWidth, labelSpace,
+		left, cross, right)
+
+	return e
+}
+
+// horizontalLine renders a line across the cells of the board using the
+// given end pieces and the joint used where each column meets the next.
+func (e *staticElements) horizontalLine(cols, cellWidth int, labelSpace, left, joint, right string) string {
+	var rowBuf bytes.Buffer
+	rowBuf.WriteString(e.rowStart)
+	rowBuf.WriteString(labelSpace)
+	rowBuf.WriteString(left)
+	for i := range cols {
+		rowBuf.WriteString(strings.Repeat(lineHorizontal, cellWidth))
+		if i != cols-1 {
+			rowBuf.WriteString(joint)
+		}
+	}
+	rowBuf.WriteString(right)
+	rowBuf.WriteString(e.rowEnd)
+	return rowBuf.String()
+}
+
+// cachedStaticElements returns the static elements for the given board and
+// options, generating them the first time they are needed.
+func (r *TextRenderer) cachedStaticElements(v BoardView, bo *BoardOptions) *staticElements {
+	key := renderKey{
+		rows:      v.Rows(),
+		cols:      v.Cols(),
+		colLabels: strings.Join(v.ColLabels(), "\x00"),
+		options:   *bo,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.cache[key]; ok {
+		return e
+	}
+	if r.cache == nil {
+		r.cache = map[renderKey]*staticElements{}
+	}
+	e := generateStaticElements(v, bo)
+	r.cache[key] = e
+	return e
+}
+
+// Render writes the box drawing layout of the board to w.
+func (r *TextRenderer) Render(w io.Writer, v BoardView) error {
+	return r.renderWithOptions(w, v, r.boardOptions(), nil)
+}
+
+// RenderBoards writes the rows of boards to w, with the boards in each row
+// side by side.
+func (r *TextRenderer) RenderBoards(w io.Writer, rows [][]BoardView) error {
+	return renderBoardRows(w, r, rows)
+}
+
+// boardOptions returns the options this renderer was created with.
+func (r *TextRenderer) boardOptions() BoardOptions {
+	if r.options == nil {
+		return DefaultBoardOptions()
+	}
+	return *r.options
+}
+
+// cellStyler lets another renderer decorate the text of each cell after it
+// has been laid out, such as with terminal color codes. It is given the
+// marker in the cell and the text of the cell in three parts, the padding on
+// the left, the marker padded to the marker width, and the padding on the
+// right. It returns the text to draw in their place, which must take up the
+// same width on screen.
+type cellStyler func(c Coord, m Marker, left, marker, right string) string
+
+// renderWithOptions writes the board to w using the given options in place
+// of the renderers own. If style is not nil, it is applied to every cell.
+func (r *TextRenderer) renderWithOptions(w io.Writer, v BoardView, opts BoardOptions, style cellStyler) error {
+	bo := opts.fitTo(v)
+	rows, cols := v.Rows(), v.Cols()
+	rowLabels := v.RowLabels()
+	e := r.cachedStaticElements(v, &bo)
+	var buf bytes.Buffer
+
+	// The cells of a winning line have the padding closest to the marker
+	// replaced with arrows pointing at it.
+	var winning map[Coord]bool
+	var winLeft, winRight string
+	if bo.HighlightWin && bo.Padding > 0 {
+		winning = map[Coord]bool{}
+		for _, c := range v.WinningLine() {
+			winning[c] = true
+		}
+		winLeft = spaces(bo.Padding-1) + winMarkerRightArrowBlack
+		winRight = winMarkerLeftArrowBlack + spaces(bo.Padding-1)
+	}
+
+	if bo.HasOuterBorder {
+		buf.WriteString(e.topOuterBorder)
+	}
+
+	if bo.HasLabels {
+		buf.WriteString(e.colLabels)
+	}
+
+	// If has inner border
+	if bo.HasInnerBorder {
+		buf.WriteString(e.topInnerBorder)
+	}
+
+	// Main board elements
+	for i := range rows {
+		buf.WriteString(e.rowStart)
+		if bo.HasLabels {
+			buf.WriteString(spaces(bo.Padding))
+			buf.WriteString(padRight(rowLabels[i], bo.LabelWidth))
+			buf.WriteString(spaces(bo.Padding))
+		}
+		if bo.HasInnerBorder {
+			buf.WriteString(lineVertical)
+		}
+
+		// For each active cell in this row of the board
+		for j := range cols {
+			c := Coord{Row: i, Col: j}
+			left, right := spaces(bo.Padding), spaces(bo.Padding)
+			if winning[c] {
+				left, right = winLeft, winRight
+			}
+			m := v.Cell(i, j)
+			marker := padRight(string(m), bo.MarkerWidth)
+			if style != nil {
+				buf.WriteString(style(c, m, left, marker, right))
+			} else {
+				buf.WriteString(left + marker + right)
+			}
+			if bo.HasInnerGrid && j != cols-1 {
+				buf.WriteString(lineVertical)
+			}
+		}
+
+		if bo.HasInnerBorder {
+			buf.WriteString(lineVertical)
+		}
+		buf.WriteString(e.rowEnd)
+
+		if bo.HasInnerGrid && i != rows-1 {
+			buf.WriteString(e.innerSeparator)
+		}
+	}
+
+	if bo.HasInnerBorder {
+		buf.WriteString(e.bottomInnerBorder)
+	}
+
+	if bo.HasLabels {
+		buf.WriteString(e.colLabels)
+	}
+
+	if bo.HasOuterBorder {
+		buf.WriteString(e.botOuterBorder)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// labelWidth returns the width of the longest label.
+func labelWidth(labels []string) int {
+	width := 0
+	for _, l := range labels {
+		width = max(width, utf8.RuneCountInString(l))
+	}
+	return width
+}
+
+// spaces returns a string of n spaces.
+func spaces(n int) string {
+	return strings.Repeat(" ", n)
+}
+
+// padRight pads s with spaces on the right to be width runes wide.
+func padRight(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + spaces(width-n)
+	}
+	return s
+}
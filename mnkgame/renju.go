@@ -0,0 +1,239 @@
+package mnkgame
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Errors returned by ApplyMove in Renju when black plays a forbidden move.
+var (
+	ErrForbiddenOverline    = errors.New("Forbidden move for black: overline")
+	ErrForbiddenDoubleFour  = errors.New("Forbidden move for black: double four")
+	ErrForbiddenDoubleThree = errors.New("Forbidden move for black: double three")
+)
+
+// RenjuRules is Gomoku where the first player, black, may not make an
+// overline, two fours, or two open threes with one move unless the move
+// also makes exactly five. The second player, white, has no restrictions
+// and also wins with an overline.
+var RenjuRules Rules = renjuRules{}
+
+// renjuDirections are the four directions a line can run through a cell:
+// horizontal, vertical, and both diagonals.
+var renjuDirections = [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+type renjuRules struct{}
+
+func (renjuRules) LegalMoves(g *MNKGame) []string {
+	open := g.board.OpenPositions()
+	if g.ToMove() != g.player1 {
+		return open
+	}
+	// forbidden tries stones in the cells, so work on a copy to leave the
+	// game untouched for anyone else reading it.
+	scratch := g.board.Clone()
+	return slices.DeleteFunc(open, func(move string) bool {
+		c, _ := scratch.decodeMove(move)
+		return scratch.forbidden(c, g.marker1) != nil
+	})
+}
+
+func (renjuRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	c, ok := g.board.decodeMove(move)
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	if g.board.cells[c.Row][c.Col] != MarkerEmpty {
+		return fmt.Errorf("Move not available")
+	}
+	if p == g.player1 {
+		if err := g.board.forbidden(c, g.marker1); err != nil {
+			return err
+		}
+	}
+
+	g.board.set(c, g.Marker(p))
+	return nil
+}
+
+func (renjuRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	black, white := g.marker1, g.marker2
+	for _, coords := range g.board.winTests {
+		// Only exactly five wins for black, while white wins with five or more.
+		if g.board.checkLine(coords, black) && !g.board.overline(coords, black) {
+			return OutcomeWin, OutcomeLoss
+		}
+		if g.board.checkLine(coords, white) {
+			return OutcomeLoss, OutcomeWin
+		}
+	}
+
+	if g.board.full() {
+		return OutcomeDraw, OutcomeDraw
+	}
+	return OutcomeIncomplete, OutcomeIncomplete
+}
+
+// checkLine reports if every cell in coords holds the marker m.
+func (b *Board) checkLine(coords Coords, m Marker) bool {
+	for _, c := range coords {
+		if b.cells[c.Row][c.Col] != m {
+			return false
+		}
+	}
+	return len(coords) > 0
+}
+
+// forbidden returns the reason playing marker m in the empty cell c is not
+// allowed for black in Renju, or nil if the move is allowed. A move that
+// makes exactly five is always allowed.
+//
+// The cells are changed while looking for lines and restored before
+// returning, so forbidden must not run on a board others are reading.
+func (b *Board) forbidden(c Coord, m Marker) error {
+	b.cells[c.Row][c.Col] = m
+	defer func() { b.cells[c.Row][c.Col] = MarkerEmpty }()
+
+	overline := false
+	for _, d := range renjuDirections {
+		switch n := b.runLength(c, d[0], d[1], m); {
+		case n == b.targetSize:
+			return nil
+		case n > b.targetSize:
+			overline = true
+		}
+	}
+	if overline {
+		return ErrForbiddenOverline
+	}
+
+	fours, threes := 0, 0
+	for _, d := range renjuDirections {
+		// A line that is already a four is not also counted as a three.
+		if n := b.fours(c, d[0], d[1], m); n > 0 {
+			fours += n
+		} else if b.three(c, d[0], d[1], m) {
+			threes++
+		}
+	}
+	if fours >= 2 {
+		return ErrForbiddenDoubleFour
+	}
+	if threes >= 2 {
+		return ErrForbiddenDoubleThree
+	}
+	return nil
+}
+
+// runLength returns the length of the unbroken run of marker m through the
+// cell c in the direction (dRow, dCol).
+func (b *Board) runLength(c Coord, dRow, dCol int, m Marker) int {
+	n := 1
+	for k := 1; b.markerAt(c.Row+k*dRow, c.Col+k*dCol) == m; k++ {
+		n++
+	}
+	for k := 1; b.markerAt(c.Row-k*dRow, c.Col-k*dCol) == m; k++ {
+		n++
+	}
+	return n
+}
+
+// fours returns the number of distinct fours through the cell c in the
+// direction (dRow, dCol). A four is a group of four of marker m that one
+// more marker would make exactly five. A straight four, open at both ends,
+// counts once, while a split line like m.mmm.m counts twice.
+func (b *Board) fours(c Coord, dRow, dCol int, m Marker) int {
+	seen := map[[2]Coord]bool{}
+	for start := -b.targetSize + 1; start <= 0; start++ {
+		var stones Coords
+		var gap Coord
+		gaps := 0
+		for k := start; k < start+b.targetSize; k++ {
+			cell := Coord{Row: c.Row + k*dRow, Col: c.Col + k*dCol}
+			if cell.Row < 0 || cell.Row >= b.rows || cell.Col < 0 || cell.Col >= b.cols {
+				gaps = b.targetSize
+				break
+			}
+			switch b.cells[cell.Row][cell.Col] {
+			case m:
+				stones = append(stones, cell)
+			case MarkerEmpty:
+				gap = cell
+				gaps++
+			default:
+				gaps = b.targetSize
+			}
+		}
+		if gaps != 1 {
+			continue
+		}
+
+		// Filling the gap must make exactly five, not an overline.
+		b.cells[gap.Row][gap.Col] = m
+		five := b.runLength(gap, dRow, dCol, m) == b.targetSize
+		b.cells[gap.Row][gap.Col] = MarkerEmpty
+		if five {
+			seen[[2]Coord{stones[0], stones[len(stones)-1]}] = true
+		}
+	}
+	return len(seen)
+}
+
+// three reports if there is an open three through the cell c in the
+// direction (dRow, dCol). That is one more marker m would make a straight
+// four, four in a row with both ends open to make exactly five, and that
+// move is not itself forbidden. A three that can only become a straight
+// four by a forbidden move does not count.
+func (b *Board) three(c Coord, dRow, dCol int, m Marker) bool {
+	for k := -b.targetSize + 1; k < b.targetSize; k++ {
+		q := Coord{Row: c.Row + k*dRow, Col: c.Col + k*dCol}
+		if k == 0 || b.markerAt(q.Row, q.Col) != MarkerEmpty ||
+			q.Row < 0 || q.Row >= b.rows || q.Col < 0 || q.Col >= b.cols {
+			continue
+		}
+
+		b.cells[q.Row][q.Col] = m
+		straight := b.straightFour(c, dRow, dCol, m)
+		b.cells[q.Row][q.Col] = MarkerEmpty
+		if straight && b.forbidden(q, m) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// straightFour reports if the run of marker m through the cell c in the
+// direction (dRow, dCol) is four long and can be made exactly five at
+// either end.
+func (b *Board) straightFour(c Coord, dRow, dCol int, m Marker) bool {
+	if b.runLength(c, dRow, dCol, m) != b.targetSize-1 {
+		return false
+	}
+
+	// Walk to the ends of the run.
+	lo, hi := 1, 1
+	for b.markerAt(c.Row-lo*dRow, c.Col-lo*dCol) == m {
+		lo++
+	}
+	for b.markerAt(c.Row+hi*dRow, c.Col+hi*dCol) == m {
+		hi++
+	}
+
+	for _, end := range []Coord{
+		{Row: c.Row - lo*dRow, Col: c.Col - lo*dCol},
+		{Row: c.Row + hi*dRow, Col: c.Col + hi*dCol},
+	} {
+		if end.Row < 0 || end.Row >= b.rows || end.Col < 0 || end.Col >= b.cols ||
+			b.cells[end.Row][end.Col] != MarkerEmpty {
+			return false
+		}
+		b.cells[end.Row][end.Col] = m
+		five := b.runLength(end, dRow, dCol, m) == b.targetSize
+		b.cells[end.Row][end.Col] = MarkerEmpty
+		if !five {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,237 @@
+package mnkgame
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rules defines how a variant of an m-n-k game lists the available moves,
+// applies a move to the board, and decides the outcome. Swapping the Rules
+// used by an MNKGame is how variants such as gravity, captures, or moving
+// pieces are supported without changing the Board.
+//
+// Rules can only be implemented inside this package, since applying a move
+// changes the cells of the board, which are not exported. Other packages
+// pick one of the predefined rule sets, such as PlacementRules or
+// GravityRules, when creating a game with NewMNKGame.
+type Rules interface {
+	// LegalMoves returns the set of moves that may be played next.
+	LegalMoves(g *MNKGame) []string
+
+	// ApplyMove applies the players move to the game. If the move is not
+	// legal, an error is returned and the game is unchanged.
+	ApplyMove(g *MNKGame, p *Player, move string) error
+
+	// Outcome reports the current outcome for player 1 and player 2.
+	Outcome(g *MNKGame) (Outcome, Outcome)
+}
+
+// winningLiner is implemented by Rules whose winning lines are not all on
+// the main board, such as lines running through the layers of a three
+// dimensional game.
+type winningLiner interface {
+	// WinningLine returns the cells of the completed line, if any.
+	WinningLine(g *MNKGame) Coords
+}
+
+// positionKeyer is implemented by Rules where the moves available depend on
+// more than the cells and the player to move, such as the small board the
+// last move sent the player to in Ultimate Tic-Tac-Toe.
+type positionKeyer interface {
+	// PositionKey returns the rest of the state that makes up the position.
+	PositionKey(g *MNKGame) string
+}
+
+// scorer is implemented by Rules where the lines on the boards do not show
+// how well a player is doing, such as lines running through the layers of a
+// three dimensional game, or Notakto where every line is to be avoided.
+type scorer interface {
+	// Score returns a rough measure of how strong the position is for
+	// player p, higher is better.
+	Score(g *MNKGame, p *Player) int
+}
+
+// turnOrder is implemented by Rules where a player may make more than one
+// move in a row, such as placing two stones a turn in Connect6. Moves by a
+// player whose turn it is not are rejected.
+type turnOrder interface {
+	// ToMove returns the player to make the next move.
+	ToMove(g *MNKGame) *Player
+}
+
+// Predefine the common rule sets.
+var (
+	// PlacementRules lets a player put their marker in any empty cell.
+	// (e.g. Tic-Tac-Toe, Gomoku)
+	PlacementRules Rules = placementRules{}
+
+	// GravityRules has a player choose only a column and the marker falls
+	// to the lowest empty row in that column. (e.g. Connect 4)
+	GravityRules Rules = gravityRules{}
+
+	// OrderChaosRules lets either player put either an X or an O in any
+	// empty cell. The first player, Order, wins with a line of one symbol,
+	// and the second player, Chaos, wins if the board fills without one.
+	// Moves are the cell followed by the symbol, e.g. "c3X" or "c3O".
+	OrderChaosRules Rules = orderChaosRules{}
+
+	// Connect6Rules has the first player place one stone on their first
+	// turn, and then each turn after places two stones in any empty cells.
+	// Each move is a single stone, and the turn passes to the other player
+	// after the second. Both stones of a turn may also be played at once by
+	// joining the cells with a plus, in either order, e.g. "j10+k11".
+	Connect6Rules Rules = multiPlacementRules{first: 1, perTurn: 2}
+)
+
+// placementRules is the default m-n-k game where any empty cell may be
+// played and the first player to get k in a row wins.
+type placementRules struct{}
+
+func (placementRules) LegalMoves(g *MNKGame) []string {
+	return g.board.OpenPositions()
+}
+
+func (placementRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	return g.board.ApplyMove(g.Marker(p), move)
+}
+
+func (placementRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	return g.board.Outcome(g.marker1, g.marker2)
+}
+
+// gravityRules takes only a column for a move, and the marker drops to
+// the lowest open row in that column.
+type gravityRules struct{}
+
+func (gravityRules) LegalMoves(g *MNKGame) []string {
+	return g.board.OpenColumns()
+}
+
+func (gravityRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	col, ok := g.board.decodeColumn(move)
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+
+	row, ok := g.board.dropRow(col)
+	if !ok {
+		return fmt.Errorf("Move not available")
+	}
+
+	g.board.set(Coord{Row: row, Col: col}, g.Marker(p))
+	return nil
+}
+
+func (gravityRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	return g.board.Outcome(g.marker1, g.marker2)
+}
+
+// orderChaosMarkers maps the symbol at the end of an Order and Chaos move to
+// the marker placed.
+var orderChaosMarkers = map[string]Marker{
+	"X": MarkerX,
+	"O": MarkerWhiteStone,
+}
+
+// orderChaosRules scores the game by role rather than by the markers the
+// players are using, since both players place both markers.
+type orderChaosRules struct{}
+
+func (orderChaosRules) LegalMoves(g *MNKGame) []string {
+	var moves []string
+	for _, pos := range g.board.OpenPositions() {
+		moves = append(moves, pos+"X", pos+"O")
+	}
+	return moves
+}
+
+func (orderChaosRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	if len(move) < 2 {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	m, ok := orderChaosMarkers[move[len(move)-1:]]
+	if !ok {
+		return fmt.Errorf("Move %q must end with the symbol to place, X or O", move)
+	}
+
+	c, ok := g.board.decodeMove(move[:len(move)-1])
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	if g.board.cells[c.Row][c.Col] != MarkerEmpty {
+		return fmt.Errorf("Move not available")
+	}
+
+	g.board.set(c, m)
+	return nil
+}
+
+func (orderChaosRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	if g.board.WinningLine() != nil {
+		return OutcomeWin, OutcomeLoss
+	}
+	if g.board.full() {
+		return OutcomeLoss, OutcomeWin
+	}
+	return OutcomeIncomplete, OutcomeIncomplete
+}
+
+// multiPlacementRules lets a player place more than one marker in a turn,
+// one marker per move. The players must take their turns in order.
+type multiPlacementRules struct {
+	// first is the number of markers placed on the first turn of the game,
+	// and perTurn is the number placed on every turn after.
+	first   int
+	perTurn int
+}
+
+// placed returns the number of markers placed so far in the game.
+func (multiPlacementRules) placed(g *MNKGame) int {
+	n := 0
+	for _, m := range g.history {
+		for _, c := range m.changes {
+			if c.to != MarkerEmpty {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// turn returns the 0-based turn the n'th marker of the game is placed on,
+// and the number of markers left to place on that turn including it.
+func (r multiPlacementRules) turn(n int) (int, int) {
+	if n < r.first {
+		return 0, r.first - n
+	}
+	n -= r.first
+	return 1 + n/r.perTurn, r.perTurn - n%r.perTurn
+}
+
+func (r multiPlacementRules) ToMove(g *MNKGame) *Player {
+	if turn, _ := r.turn(r.placed(g)); turn%2 == 1 {
+		return g.player2
+	}
+	return g.player1
+}
+
+func (multiPlacementRules) LegalMoves(g *MNKGame) []string {
+	return g.board.OpenPositions()
+}
+
+func (r multiPlacementRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	cells := strings.Split(move, "+")
+	if _, left := r.turn(r.placed(g)); len(cells) > left {
+		return fmt.Errorf("This turn has %d stones left to place, %q has %d", left, move, len(cells))
+	}
+	for _, cell := range cells {
+		if err := g.board.ApplyMove(g.Marker(p), cell); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (multiPlacementRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	return g.board.Outcome(g.marker1, g.marker2)
+}
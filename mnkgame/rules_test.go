@@ -0,0 +1,474 @@
+package mnkgame
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestGravityRules(t *testing.T) {
+	tests := []struct {
+		cells     [][]Marker
+		colLabels []string
+		move      string
+		want      Coord
+		wantErr   bool
+		wantMoves []string
+	}{
+		{
+			// Empty column, marker falls to the bottom row.
+			cells: [][]Marker{
+				[]Marker{MarkerEmpty, MarkerEmpty},
+				[]Marker{MarkerEmpty, MarkerEmpty},
+				[]Marker{MarkerEmpty, MarkerEmpty},
+			},
+			move:      "2",
+			want:      Coord{Row: 2, Col: 1},
+			wantMoves: []string{"1", "2"},
+		},
+		{
+			// Partially filled column, marker stacks on top.
+			cells: [][]Marker{
+				[]Marker{MarkerEmpty, MarkerEmpty},
+				[]Marker{MarkerEmpty, MarkerEmpty},
+				[]Marker{MarkerX, MarkerEmpty},
+			},
+			colLabels: []string{"a", "b"},
+			move:      "a",
+			want:      Coord{Row: 1, Col: 0},
+			wantMoves: []string{"a", "b"},
+		},
+		{
+			// Full column can't be played and isn't listed.
+			cells: [][]Marker{
+				[]Marker{MarkerWhiteStone, MarkerEmpty},
+				[]Marker{MarkerX, MarkerEmpty},
+			},
+			move:      "1",
+			wantErr:   true,
+			wantMoves: []string{"2"},
+		},
+		{
+			// Row and column style moves are not accepted.
+			cells: [][]Marker{
+				[]Marker{MarkerEmpty, MarkerEmpty},
+				[]Marker{MarkerEmpty, MarkerEmpty},
+			},
+			move:      "1,1",
+			wantErr:   true,
+			wantMoves: []string{"1", "2"},
+		},
+	}
+
+	for _, test := range tests {
+		g := NewMNKGame("test", len(test.cells), len(test.cells[0]), 2,
+			GravityRules, Player1, Player2)
+		g.board.cells = test.cells
+		g.board.SetLabels([]string{"", "", ""}, test.colLabels)
+
+		if got := g.PotentialMoves(); !cmp.Equal(got, test.wantMoves, cmpopts.EquateEmpty()) {
+			t.Errorf("PotentialMoves() = %v, want %v", got, test.wantMoves)
+		}
+
+		err := g.ApplyMove(Player1, test.move)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ApplyMove(player, %q) error = %v, want error %v",
+				test.move, err, test.wantErr)
+			continue
+		}
+		if err == nil && g.board.cells[test.want.Row][test.want.Col] != g.Marker(Player1) {
+			t.Errorf("ApplyMove(player, %q) did not fill %v", test.move, test.want)
+		}
+	}
+}
+
+func TestPlacementRules(t *testing.T) {
+	g := NewMNKGame("test", 2, 2, 2, nil, Player1, Player2)
+
+	if got, want := len(g.PotentialMoves()), 4; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+	if err := g.ApplyMove(Player1, "2,2"); err != nil {
+		t.Errorf("ApplyMove(player, %q) = %v, want nil", "2,2", err)
+	}
+	if err := g.ApplyMove(Player2, "2,2"); err == nil {
+		t.Errorf("ApplyMove(player, %q) on a filled cell = nil, want error", "2,2")
+	}
+	if got, want := g.PotentialMoves(), []string{"1,1", "1,2", "2,1"}; !cmp.Equal(got, want) {
+		t.Errorf("PotentialMoves() = %v, want %v", got, want)
+	}
+}
+
+func TestRenjuRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		black []Coord
+		white []Coord
+		// player is 1 for black or 2 for white.
+		player  int
+		move    string
+		wantErr error
+	}{
+		{
+			name:   "single three",
+			black:  []Coord{{Row: 7, Col: 5}, {Row: 7, Col: 6}},
+			player: 1,
+			move:   "h8",
+		},
+		{
+			name:    "double three",
+			black:   []Coord{{Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 5, Col: 7}, {Row: 6, Col: 7}},
+			player:  1,
+			move:    "h8",
+			wantErr: ErrForbiddenDoubleThree,
+		},
+		{
+			name:    "split double three",
+			black:   []Coord{{Row: 7, Col: 4}, {Row: 7, Col: 6}, {Row: 4, Col: 7}, {Row: 6, Col: 7}},
+			player:  1,
+			move:    "h8",
+			wantErr: ErrForbiddenDoubleThree,
+		},
+		{
+			// The row's only straight four point, h9, is an overline in
+			// its column, so the row is not a three.
+			name: "three made straight only by a forbidden move",
+			black: []Coord{
+				{Row: 7, Col: 6}, {Row: 7, Col: 9},
+				{Row: 5, Col: 7}, {Row: 6, Col: 7},
+				{Row: 4, Col: 8}, {Row: 5, Col: 8}, {Row: 6, Col: 8}, {Row: 8, Col: 8}, {Row: 9, Col: 8},
+			},
+			player: 1,
+			move:   "h8",
+		},
+		{
+			// A three blocked at one end can't become a straight four.
+			name:   "blocked three",
+			black:  []Coord{{Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 5, Col: 7}, {Row: 6, Col: 7}},
+			white:  []Coord{{Row: 7, Col: 4}, {Row: 7, Col: 8}},
+			player: 1,
+			move:   "h8",
+		},
+		{
+			name:    "double four",
+			black:   []Coord{{Row: 7, Col: 4}, {Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 4, Col: 7}, {Row: 5, Col: 7}, {Row: 6, Col: 7}},
+			player:  1,
+			move:    "h8",
+			wantErr: ErrForbiddenDoubleFour,
+		},
+		{
+			name:    "double four in one line",
+			black:   []Coord{{Row: 7, Col: 3}, {Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 7, Col: 9}},
+			player:  1,
+			move:    "h8",
+			wantErr: ErrForbiddenDoubleFour,
+		},
+		{
+			name:   "four three",
+			black:  []Coord{{Row: 7, Col: 4}, {Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 5, Col: 7}, {Row: 6, Col: 7}},
+			player: 1,
+			move:   "h8",
+		},
+		{
+			name:    "overline",
+			black:   []Coord{{Row: 7, Col: 2}, {Row: 7, Col: 3}, {Row: 7, Col: 4}, {Row: 7, Col: 6}, {Row: 7, Col: 7}},
+			player:  1,
+			move:    "h6",
+			wantErr: ErrForbiddenOverline,
+		},
+		{
+			// Exactly five wins even if it also makes a double four.
+			name:   "five",
+			black:  []Coord{{Row: 7, Col: 3}, {Row: 7, Col: 4}, {Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 4, Col: 7}, {Row: 5, Col: 7}, {Row: 6, Col: 7}},
+			player: 1,
+			move:   "h8",
+		},
+		{
+			name:   "white double three",
+			black:  []Coord{{Row: 0, Col: 0}},
+			white:  []Coord{{Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 5, Col: 7}, {Row: 6, Col: 7}},
+			player: 2,
+			move:   "h8",
+		},
+		{
+			name:   "white overline",
+			white:  []Coord{{Row: 7, Col: 2}, {Row: 7, Col: 3}, {Row: 7, Col: 4}, {Row: 7, Col: 6}, {Row: 7, Col: 7}},
+			player: 2,
+			move:   "h6",
+		},
+	}
+
+	for _, test := range tests {
+		black, white := &Player{}, &Player{}
+		g := Renju(black, white)
+		for _, c := range test.black {
+			g.board.cells[c.Row][c.Col] = g.Marker(black)
+		}
+		for _, c := range test.white {
+			g.board.cells[c.Row][c.Col] = g.Marker(white)
+		}
+
+		p := black
+		if test.player == 2 {
+			p = white
+			// Play a move elsewhere so it is whites turn.
+			g.ApplyMove(black, "o15")
+		}
+
+		listed := slices.Contains(g.PotentialMoves(), test.move)
+		if want := test.wantErr == nil; listed != want {
+			t.Errorf("%s: PotentialMoves() contains %q = %v, want %v", test.name, test.move, listed, want)
+		}
+
+		err := g.ApplyMove(p, test.move)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%s: ApplyMove(%q) = %v, want %v", test.name, test.move, err, test.wantErr)
+		}
+	}
+}
+
+func TestRenjuOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		black  []Coord
+		white  []Coord
+		wantP1 Outcome
+		wantP2 Outcome
+	}{
+		{
+			name:   "black five",
+			black:  []Coord{{Row: 7, Col: 3}, {Row: 7, Col: 4}, {Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 7, Col: 7}},
+			wantP1: OutcomeWin,
+			wantP2: OutcomeLoss,
+		},
+		{
+			name:   "black overline",
+			black:  []Coord{{Row: 7, Col: 2}, {Row: 7, Col: 3}, {Row: 7, Col: 4}, {Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 7, Col: 7}},
+			wantP1: OutcomeIncomplete,
+			wantP2: OutcomeIncomplete,
+		},
+		{
+			name:   "white overline",
+			white:  []Coord{{Row: 7, Col: 2}, {Row: 7, Col: 3}, {Row: 7, Col: 4}, {Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 7, Col: 7}},
+			wantP1: OutcomeLoss,
+			wantP2: OutcomeWin,
+		},
+	}
+
+	for _, test := range tests {
+		black, white := &Player{}, &Player{}
+		g := Renju(black, white)
+		for _, c := range test.black {
+			g.board.cells[c.Row][c.Col] = g.Marker(black)
+		}
+		for _, c := range test.white {
+			g.board.cells[c.Row][c.Col] = g.Marker(white)
+		}
+
+		if p1, p2 := g.Outcomes(); p1 != test.wantP1 || p2 != test.wantP2 {
+			t.Errorf("%s: Outcomes() = %v, %v, want %v, %v", test.name, p1, p2, test.wantP1, test.wantP2)
+		}
+	}
+}
+
+func TestRenjuPotentialMovesConcurrent(t *testing.T) {
+	// Looking for black's forbidden moves must not touch the game, so it can
+	// be read from several goroutines, such as the engines searching it.
+	black, white := &Player{}, &Player{}
+	g := Renju(black, white)
+	for _, c := range []Coord{{Row: 7, Col: 5}, {Row: 7, Col: 6}, {Row: 5, Col: 7}, {Row: 6, Col: 7}} {
+		g.board.cells[c.Row][c.Col] = g.Marker(black)
+	}
+	want := g.PotentialMoves()
+	wantBoard := g.board.String()
+
+	var wg sync.WaitGroup
+	errs := make(chan string, 100)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if got := g.PotentialMoves(); !cmp.Equal(got, want) {
+				errs <- fmt.Sprintf("PotentialMoves() = %v, want %v", got, want)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if got := g.board.String(); got != wantBoard {
+				errs <- fmt.Sprintf("String() =\n%s\nwant\n%s", got, wantBoard)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestOrderChaosRules(t *testing.T) {
+	g := OrderAndChaos(Player1, Player2)
+
+	if got, want := len(g.PotentialMoves()), 6*6*2; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+
+	tests := []struct {
+		move    string
+		want    Marker
+		wantErr bool
+	}{
+		{move: "a1X", want: MarkerX},
+		{move: "a2O", want: MarkerWhiteStone},
+		// Filled cell.
+		{move: "a1O", wantErr: true},
+		// No symbol or an unknown one.
+		{move: "a3", wantErr: true},
+		{move: "a3Z", wantErr: true},
+		{move: "z3X", wantErr: true},
+		{move: "X", wantErr: true},
+	}
+	for _, test := range tests {
+		err := g.ApplyMove(g.ToMove(), test.move)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ApplyMove(%q) error = %v, want error %v", test.move, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		c, _ := g.board.decodeMove(test.move[:len(test.move)-1])
+		if got := g.board.cells[c.Row][c.Col]; got != test.want {
+			t.Errorf("ApplyMove(%q) placed %q, want %q", test.move, got, test.want)
+		}
+	}
+}
+
+func TestOrderChaosOutcome(t *testing.T) {
+	x, o := MarkerX, MarkerWhiteStone
+	tests := []struct {
+		name   string
+		cells  [][]Marker
+		wantP1 Outcome
+		wantP2 Outcome
+	}{
+		{
+			name: "empty",
+			cells: [][]Marker{
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+			},
+			wantP1: OutcomeIncomplete,
+			wantP2: OutcomeIncomplete,
+		},
+		{
+			// Order wins with a line of either symbol.
+			name: "five Os",
+			cells: [][]Marker{
+				{MarkerEmpty, o, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, o, MarkerEmpty, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, o, MarkerEmpty, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, o, MarkerEmpty},
+				{MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, MarkerEmpty, o},
+				{x, x, x, x, MarkerEmpty, MarkerEmpty},
+			},
+			wantP1: OutcomeWin,
+			wantP2: OutcomeLoss,
+		},
+		{
+			// Chaos wins when the board fills with no line.
+			name: "full",
+			cells: [][]Marker{
+				{x, x, o, o, x, x},
+				{o, o, x, x, o, o},
+				{x, x, o, o, x, x},
+				{o, o, x, x, o, o},
+				{x, x, o, o, x, x},
+				{o, o, x, x, o, o},
+			},
+			wantP1: OutcomeLoss,
+			wantP2: OutcomeWin,
+		},
+	}
+
+	for _, test := range tests {
+		g := OrderAndChaos(Player1, Player2)
+		g.board.cells = test.cells
+		if p1, p2 := g.Outcomes(); p1 != test.wantP1 || p2 != test.wantP2 {
+			t.Errorf("%s: Outcomes() = %v, %v, want %v, %v", test.name, p1, p2, test.wantP1, test.wantP2)
+		}
+	}
+}
+
+func TestConnect6Rules(t *testing.T) {
+	black, white := &Player{}, &Player{}
+	g := Connect6(black, white)
+
+	tests := []struct {
+		player  *Player
+		move    string
+		wantErr bool
+		// wantNext is the player to move after the move.
+		wantNext *Player
+	}{
+		// The first turn is a single stone.
+		{player: black, move: "j10+j11", wantErr: true, wantNext: black},
+		{player: black, move: "j10", wantNext: white},
+		// Out of turn.
+		{player: black, move: "a1", wantErr: true, wantNext: white},
+		// Two stones a turn, one at a time.
+		{player: white, move: "a1", wantNext: white},
+		{player: white, move: "a2", wantNext: black},
+		// Or both at once, in either order.
+		{player: black, move: "j12+j11", wantNext: white},
+		{player: white, move: "b1+b1", wantErr: true, wantNext: white},
+		{player: white, move: "b1+j10", wantErr: true, wantNext: white},
+		{player: white, move: "b1+b2+b3", wantErr: true, wantNext: white},
+		{player: white, move: "b1+b2", wantNext: black},
+		// Only the one stone left in the turn.
+		{player: black, move: "j13", wantNext: black},
+		{player: black, move: "j14+j15", wantErr: true, wantNext: black},
+		{player: black, move: "j14", wantNext: white},
+		{player: white, move: "c1+c2", wantNext: black},
+		{player: black, move: "j15", wantNext: black},
+	}
+	for _, test := range tests {
+		err := g.ApplyMove(test.player, test.move)
+		if (err != nil) != test.wantErr {
+			t.Errorf("ApplyMove(%q) error = %v, want error %v", test.move, err, test.wantErr)
+		}
+		if got := g.ToMove(); got != test.wantNext {
+			t.Errorf("after %q, ToMove() = %v, want %v", test.move, got, test.wantNext)
+		}
+	}
+
+	// Black has six in a row with the first stone of the turn.
+	if got := g.Outcome(black); got != OutcomeWin {
+		t.Errorf("Outcome(black) = %v, want %v", got, OutcomeWin)
+	}
+	if got, want := g.MovesPlayed(), 9; got != want {
+		t.Errorf("MovesPlayed() = %d, want %d", got, want)
+	}
+
+	// Each move is one cell, and Undo takes back one move.
+	g.Undo()
+	if got := g.board.cells[9][14]; got != MarkerEmpty {
+		t.Errorf("after Undo cell j15 = %q, want %q", got, MarkerEmpty)
+	}
+	if got, want := len(g.PotentialMoves()), 19*19-11; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+	if got := g.ToMove(); got != black {
+		t.Errorf("after Undo ToMove() = %v, want %v", got, black)
+	}
+}
@@ -0,0 +1,94 @@
+package mnkgame
+
+import (
+	"fmt"
+	"slices"
+)
+
+// BoardSnapshot is a saved copy of the cells of a board that can later be
+// put back with Restore. It is much cheaper than cloning the whole board.
+type BoardSnapshot struct {
+	rows  int
+	cols  int
+	cells []Marker
+}
+
+// Snapshot saves the current state of the cells on the board.
+func (b *Board) Snapshot() BoardSnapshot {
+	s := BoardSnapshot{
+		rows:  b.rows,
+		cols:  b.cols,
+		cells: make([]Marker, 0, b.rows*b.cols),
+	}
+	for _, row := range b.cells {
+		s.cells = append(s.cells, row...)
+	}
+	return s
+}
+
+// Restore puts the cells back to the state saved in the snapshot. An error
+// is returned if the snapshot was taken from a board of different dimensions.
+func (b *Board) Restore(s BoardSnapshot) error {
+	if err := b.fits(s); err != nil {
+		return err
+	}
+	for i, row := range b.cells {
+		copy(row, s.cells[i*b.cols:(i+1)*b.cols])
+	}
+	return nil
+}
+
+// fits returns an error if the snapshot was taken from a board of different
+// dimensions.
+func (b *Board) fits(s BoardSnapshot) error {
+	if s.rows != b.rows || s.cols != b.cols {
+		return fmt.Errorf("Snapshot is %dx%d, board is %dx%d", s.rows, s.cols, b.rows, b.cols)
+	}
+	return nil
+}
+
+// Snapshot is a saved state of a game, its board and move history, that can
+// later be put back with Restore.
+type Snapshot struct {
+	board   BoardSnapshot
+	boards  []BoardSnapshot
+	history []Move
+	undone  []Move
+}
+
+// Snapshot saves the current state of the game.
+func (t *MNKGame) Snapshot() Snapshot {
+	s := Snapshot{
+		board:   t.board.Snapshot(),
+		history: slices.Clone(t.history),
+		undone:  slices.Clone(t.undone),
+	}
+	for _, b := range t.boards {
+		s.boards = append(s.boards, b.Snapshot())
+	}
+	return s
+}
+
+// Restore puts the game back to the state saved in the snapshot. An error is
+// returned if the snapshot was taken from a game with a different board size,
+// in which case the game is unchanged.
+func (t *MNKGame) Restore(s Snapshot) error {
+	if len(s.boards) != len(t.boards) {
+		return fmt.Errorf("Snapshot has %d sub-boards, game has %d", len(s.boards), len(t.boards))
+	}
+
+	// Check every board before changing any of them.
+	snaps := append([]BoardSnapshot{s.board}, s.boards...)
+	boards := t.allBoards()
+	for i, b := range boards {
+		if err := b.fits(snaps[i]); err != nil {
+			return err
+		}
+	}
+	for i, b := range boards {
+		b.Restore(snaps[i])
+	}
+	t.history = slices.Clone(s.history)
+	t.undone = slices.Clone(s.undone)
+	return nil
+}
@@ -0,0 +1,101 @@
+package mnkgame
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBoardClone(t *testing.T) {
+	b := newBoard(3, 3, 3)
+	b.SetLabels([]string{"T", "C", "B"}, []string{"L", "C", "R"})
+
+	c := b.Clone()
+	if err := c.ApplyMove(MarkerX, "CC"); err != nil {
+		t.Fatalf("ApplyMove(%q) on clone error = %v", "CC", err)
+	}
+	c.SetLabels([]string{"1", "2", "3"}, []string{"a", "b", "c"})
+
+	if b.cells[1][1] != MarkerEmpty {
+		t.Errorf("ApplyMove() on clone changed the original board")
+	}
+	if got, want := b.OpenPositions()[0], "TL"; got != want {
+		t.Errorf("SetLabels() on clone changed the original labels, got %q, want %q", got, want)
+	}
+}
+
+func TestGameClone(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	game.ApplyMove(game.ToMove(), "TL")
+
+	c := game.Clone()
+	c.ApplyMove(c.ToMove(), "CC")
+	c.Undo()
+	c.Undo()
+
+	if got, want := game.MovesPlayed(), 1; got != want {
+		t.Errorf("MovesPlayed() after changing the clone = %d, want %d", got, want)
+	}
+	if game.board.cells[0][0] != game.Marker(Player1) {
+		t.Errorf("Undo() on clone changed the original board")
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	game := TicTacToe(Player1, Player2)
+	game.ApplyMove(game.ToMove(), "TL")
+	snap := game.Snapshot()
+	wantMoves := game.PotentialMoves()
+
+	for _, m := range []string{"CC", "BR"} {
+		if err := game.ApplyMove(game.ToMove(), m); err != nil {
+			t.Fatalf("ApplyMove(%q) error = %v", m, err)
+		}
+	}
+
+	if err := game.Restore(snap); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := game.PotentialMoves(); !cmp.Equal(got, wantMoves) {
+		t.Errorf("PotentialMoves() after Restore() = %v, want %v", got, wantMoves)
+	}
+	if got, want := game.MovesPlayed(), 1; got != want {
+		t.Errorf("MovesPlayed() after Restore() = %d, want %d", got, want)
+	}
+	if got := game.ToMove(); got != Player2 {
+		t.Errorf("ToMove() after Restore() = %v, want %v", got, Player2)
+	}
+
+	// The snapshot can be restored more than once.
+	game.ApplyMove(game.ToMove(), "CC")
+	if err := game.Restore(snap); err != nil {
+		t.Fatalf("Restore() again error = %v", err)
+	}
+	if got := game.PotentialMoves(); !cmp.Equal(got, wantMoves) {
+		t.Errorf("PotentialMoves() after second Restore() = %v, want %v", got, wantMoves)
+	}
+
+	// Snapshots only fit boards of the same size.
+	if err := Connect4(Player1, Player2).Restore(snap); err == nil {
+		t.Errorf("Restore() onto a different size board = nil, want error")
+	}
+}
+
+func TestSnapshotRestoreSubBoards(t *testing.T) {
+	game := Notakto(3, Player1, Player2)
+	snap := game.Snapshot()
+	game.ApplyMove(game.ToMove(), "3CC")
+	want := game.PositionKey()
+
+	// Only the last sub-board does not fit, and the game is left as it was.
+	snap.boards[2] = newBoard(2, 2, 2).Snapshot()
+	if err := game.Restore(snap); err == nil {
+		t.Errorf("Restore() with a different size sub-board = nil, want error")
+	}
+	if got := game.PositionKey(); got != want {
+		t.Errorf("PositionKey() after failed Restore() = %q, want %q", got, want)
+	}
+	if got, want := game.MovesPlayed(), 1; got != want {
+		t.Errorf("MovesPlayed() after failed Restore() = %d, want %d", got, want)
+	}
+}
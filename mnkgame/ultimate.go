@@ -0,0 +1,125 @@
+package mnkgame
+
+import "fmt"
+
+// UltimateRules is tic-tac-toe played on a 3x3 grid of tic-tac-toe boards.
+// Winning a small board claims its cell on the main board, and three claimed
+// cells in a row wins the game. The cell a player picks on a small board
+// sends their opponent to play on the small board in the same place, unless
+// that board is already decided, in which case any open board may be used.
+//
+// Moves are the cell of the small board on the main board followed by the
+// cell on the small board, both using the tic-tac-toe labels, e.g. "TLCC"
+// for the center of the top left board.
+var UltimateRules Rules = ultimateRules{}
+
+type ultimateRules struct{}
+
+// boardIndex returns the index into the games sub-boards of the small board
+// in the given cell of the main board.
+func (ultimateRules) boardIndex(g *MNKGame, c Coord) int {
+	return c.Row*g.board.cols + c.Col
+}
+
+// decided reports if the small board i has been won or filled.
+func (ultimateRules) decided(g *MNKGame, i int) bool {
+	return g.board.cells[i/g.board.cols][i%g.board.cols] != MarkerEmpty
+}
+
+// sentTo returns the index of the small board the next move must be played
+// on, or false if any open board may be played.
+func (r ultimateRules) sentTo(g *MNKGame) (int, bool) {
+	if len(g.history) == 0 {
+		return 0, false
+	}
+	last := g.history[len(g.history)-1]
+	if last.Board == 0 {
+		return 0, false
+	}
+	i := r.boardIndex(g, last.Coord)
+	if r.decided(g, i) {
+		return 0, false
+	}
+	return i, true
+}
+
+// PositionKey adds the small board the next move is sent to, since the same
+// cells can be reached with the next move sent to different boards.
+func (r ultimateRules) PositionKey(g *MNKGame) string {
+	if i, sent := r.sentTo(g); sent {
+		return fmt.Sprintf("@%d", i)
+	}
+	return ""
+}
+
+func (r ultimateRules) LegalMoves(g *MNKGame) []string {
+	target, sent := r.sentTo(g)
+
+	var moves []string
+	for i, b := range g.boards {
+		if r.decided(g, i) || (sent && i != target) {
+			continue
+		}
+		label := g.board.rowLabels[i/g.board.cols] + g.board.colLabels[i%g.board.cols]
+		for _, pos := range b.OpenPositions() {
+			moves = append(moves, label+pos)
+		}
+	}
+	return moves
+}
+
+func (r ultimateRules) ApplyMove(g *MNKGame, p *Player, move string) error {
+	// The main board cell label is the row and column label.
+	n := g.board.rowLabelSize + 1
+	if len(move) <= n {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	mc, ok := g.board.decodeMove(move[:n])
+	if !ok {
+		return fmt.Errorf("Unable to decipher the requested move: %q", move)
+	}
+	i := r.boardIndex(g, mc)
+	if r.decided(g, i) {
+		return fmt.Errorf("Board %s is already decided", move[:n])
+	}
+	if target, sent := r.sentTo(g); sent && i != target {
+		return fmt.Errorf("Move must be played on board %s%s",
+			g.board.rowLabels[target/g.board.cols], g.board.colLabels[target%g.board.cols])
+	}
+
+	b := g.boards[i]
+	if err := b.ApplyMove(g.Marker(p), move[n:]); err != nil {
+		return err
+	}
+
+	// Claim the cell on the main board if the small board is now decided.
+	switch {
+	case b.WinningLine() != nil:
+		g.board.set(mc, g.Marker(p))
+	case b.full():
+		g.board.set(mc, MarkerBlocked)
+	}
+	return nil
+}
+
+func (ultimateRules) Outcome(g *MNKGame) (Outcome, Outcome) {
+	return g.board.Outcome(g.marker1, g.marker2)
+}
+
+// UltimateTicTacToe returns a new instance of ultimate tic-tac-toe. The main
+// board holds who has claimed each of the nine small boards, with drawn
+// boards blocked.
+func UltimateTicTacToe(p1, p2 *Player) *MNKGame {
+	g := NewMNKGame("Ultimate Tic-Tac-Toe", 3, 3, 3, UltimateRules, p1, p2)
+
+	g.setMarkers(MarkerX, MarkerWhiteStone)
+
+	rowLabels, colLabels := []string{"T", "C", "B"}, []string{"L", "C", "R"}
+	g.board.SetLabels(rowLabels, colLabels)
+	g.addBoards(9, 3, 3, 3)
+	for _, b := range g.boards {
+		b.SetLabels(rowLabels, colLabels)
+	}
+
+	return g
+}
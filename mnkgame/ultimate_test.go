@@ -0,0 +1,108 @@
+package mnkgame
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUltimateSentTo(t *testing.T) {
+	p1, p2 := &Player{}, &Player{}
+	g := UltimateTicTacToe(p1, p2)
+
+	if got, want := len(g.PotentialMoves()), 81; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+
+	// Player 1 already has two in the top row of the top left board.
+	tl := g.boards[0]
+	tl.cells[0][0], tl.cells[0][1] = g.Marker(p1), g.Marker(p1)
+
+	if err := g.ApplyMove(p1, "TLTR"); err != nil {
+		t.Fatalf("ApplyMove(%q) error = %v", "TLTR", err)
+	}
+	if got := g.board.cells[0][0]; got != g.Marker(p1) {
+		t.Errorf("after winning the top left board, main cell TL = %q, want %q", got, g.Marker(p1))
+	}
+
+	// Player 2 is sent to the top right board.
+	moves := g.PotentialMoves()
+	if len(moves) != 9 {
+		t.Errorf("len(PotentialMoves()) = %d, want 9", len(moves))
+	}
+	for _, m := range moves {
+		if !strings.HasPrefix(m, "TR") {
+			t.Errorf("PotentialMoves() has %q, want only moves on board TR", m)
+		}
+	}
+	if err := g.ApplyMove(p2, "CCCC"); err == nil {
+		t.Errorf("ApplyMove(%q) on the wrong board = nil, want error", "CCCC")
+	}
+	if err := g.ApplyMove(p2, "TLBB"); err == nil {
+		t.Errorf("ApplyMove(%q) on a decided board = nil, want error", "TLBB")
+	}
+
+	// Sending player 1 to the decided top left board lets them play anywhere
+	// open.
+	if err := g.ApplyMove(p2, "TRTL"); err != nil {
+		t.Fatalf("ApplyMove(%q) error = %v", "TRTL", err)
+	}
+	if got, want := len(g.PotentialMoves()), 8+7*9; got != want {
+		t.Errorf("len(PotentialMoves()) = %d, want %d", got, want)
+	}
+}
+
+func TestUltimateOutcome(t *testing.T) {
+	p1, p2 := &Player{}, &Player{}
+	g := UltimateTicTacToe(p1, p2)
+
+	// A filled small board with no line is blocked on the main board.
+	o := g.Marker(p2)
+	x := g.Marker(p1)
+	g.boards[4].cells = [][]Marker{
+		{x, o, x},
+		{x, o, o},
+		{o, x, MarkerEmpty},
+	}
+	if err := g.ApplyMove(p1, "CCBR"); err != nil {
+		t.Fatalf("ApplyMove(%q) error = %v", "CCBR", err)
+	}
+	if got := g.board.cells[1][1]; got != MarkerBlocked {
+		t.Errorf("main cell CC = %q, want %q", got, MarkerBlocked)
+	}
+
+	// Player 1 has the top left and top center and takes the top right.
+	g.board.cells[0][0], g.board.cells[0][1] = x, x
+	g.boards[2].cells[2][0], g.boards[2].cells[2][1] = x, x
+	g.ApplyMove(p2, "BRTR")
+	if got := g.Outcome(p1); got != OutcomeIncomplete {
+		t.Errorf("Outcome(p1) = %v, want %v", got, OutcomeIncomplete)
+	}
+	if err := g.ApplyMove(p1, "TRBR"); err != nil {
+		t.Fatalf("ApplyMove(%q) error = %v", "TRBR", err)
+	}
+	if got := g.Outcome(p1); got != OutcomeWin {
+		t.Errorf("Outcome(p1) = %v, want %v", got, OutcomeWin)
+	}
+
+	// Undo gives the small board and the game back.
+	g.Undo()
+	if got := g.board.cells[0][2]; got != MarkerEmpty {
+		t.Errorf("after Undo, main cell TR = %q, want %q", got, MarkerEmpty)
+	}
+}
+
+func TestUltimateRender(t *testing.T) {
+	g := UltimateTicTacToe(&Player{}, &Player{})
+	g.ApplyMove(g.ToMove(), "CCCC")
+
+	single := strings.Split(strings.TrimRight(TicTacToe(&Player{}, &Player{}).RenderWithOptions(CompactBoardOptions()), "\n"), "\n")
+	got := strings.Split(strings.TrimRight(g.RenderWithOptions(CompactBoardOptions()), "\n"), "\n")
+
+	// Three rows of boards with a blank line between them.
+	if want := 3*len(single) + 2; len(got) != want {
+		t.Fatalf("RenderWithOptions() has %d lines, want %d:\n%s", len(got), want, strings.Join(got, "\n"))
+	}
+	if got := got[len(single)]; got != "" {
+		t.Errorf("RenderWithOptions() line %d = %q, want a blank line", len(single), got)
+	}
+}